@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/josesalasdev/go-schema/validator"
+)
+
+func runCheck(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	inputs, err := readInputs(fs.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema check: %v\n", err)
+		return exitError
+	}
+
+	// Load every file first so that schemas can reference each other by
+	// base name regardless of the order they were given in.
+	registry := validator.NewRegistry()
+	schemas := make([]validator.Schema, len(inputs))
+	parseErrs := make([]error, len(inputs))
+	for i, in := range inputs {
+		schemas[i], parseErrs[i] = registry.LoadSchema(schemaName(in.name), bytes.NewReader(in.data))
+	}
+
+	code := exitOK
+	for i, in := range inputs {
+		if err := parseErrs[i]; err != nil {
+			fmt.Fprintf(stdout, "%s: %v\n", in.name, err)
+			code = exitInvalid
+			continue
+		}
+
+		// Every problem is printed on its own line; only errors fail the file.
+		valid := true
+		for _, problem := range registry.SchemaProblems(schemas[i]) {
+			fmt.Fprintf(stdout, "%s: %s\n", in.name, problem)
+			if problem.Severity == validator.SeverityError {
+				valid = false
+			}
+		}
+		if !valid {
+			code = exitInvalid
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: ok\n", in.name)
+	}
+	return code
+}
+
+// schemaName returns the registry name of a schema file: its base name
+// without extension.
+func schemaName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCommand(t *testing.T) {
+	good := writeFile(t, "good.json", testSchema)
+	badType := writeFile(t, "bad_type.json", `{"age": {"type": "number"}}`)
+	badJSON := writeFile(t, "bad_json.json", `{"age": `)
+
+	code, out, _ := runCLI("", "check", good)
+	if code != exitOK || out != good+": ok\n" {
+		t.Errorf("good schema: code %d, output %q", code, out)
+	}
+
+	code, out, _ = runCLI("", "check", good, badType, badJSON)
+	if code != exitInvalid {
+		t.Errorf("Expected exit code %d, got %d", exitInvalid, code)
+	}
+	if !strings.Contains(out, badType+": age: invalid type 'number'") || !strings.Contains(out, badJSON+": invalid schema JSON") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	code, out, _ = runCLI(`{"x": {"type": "bool"}}`, "check")
+	if code != exitOK || out != "<stdin>: ok\n" {
+		t.Errorf("stdin: code %d, output %q", code, out)
+	}
+}
+
+func TestCheckCommandProblems(t *testing.T) {
+	schema := writeFile(t, "schema.json", `{
+		"name": {"type": "string", "min_length": 5, "max_length": 2},
+		"age": {"type": "int", "min_length": 1},
+		"nick": {"type": "string", "messages": {"required": "Nick is required"}}
+	}`)
+	warned := writeFile(t, "warned.json", `{"nick": {"type": "string", "messages": {"required": "Nick is required"}}}`)
+
+	code, out, _ := runCLI("", "check", schema)
+	want := schema + ": age: min_length/max_length can only be used for string fields\n" +
+		schema + ": name: min_length 5 is greater than max_length 2\n" +
+		schema + ": nick: warning: messages.required never applies because the field is not required\n"
+	if code != exitInvalid || out != want {
+		t.Errorf("code %d, output:\n%s", code, out)
+	}
+
+	code, out, _ = runCLI("", "check", warned)
+	if code != exitOK || !strings.HasSuffix(out, warned+": ok\n") || !strings.Contains(out, "warning:") {
+		t.Errorf("Expected warnings not to fail the check, got code %d:\n%s", code, out)
+	}
+}
+
+func TestCheckCommandRefs(t *testing.T) {
+	user := writeFile(t, "user.json", `{"home": {"ref": "address"}}`)
+	address := writeFile(t, "address.json", `{"zip": {"type": "string"}}`)
+
+	code, out, _ := runCLI("", "check", user, address)
+	if code != exitOK {
+		t.Errorf("Expected refs across files to resolve, got code %d:\n%s", code, out)
+	}
+
+	code, out, _ = runCLI("", "check", user)
+	if code != exitInvalid || !strings.Contains(out, "unresolved reference 'address'") {
+		t.Errorf("Expected dangling reference, got code %d:\n%s", code, out)
+	}
+}
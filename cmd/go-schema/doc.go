@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/josesalasdev/go-schema/validator"
+)
+
+func runDoc(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("doc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "markdown", "output format: markdown or html")
+	var includes []string
+	fs.Func("include", "schema file that the documented schemas may reference by base name (repeatable)", func(path string) error {
+		includes = append(includes, path)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "markdown" && *format != "html" {
+		fmt.Fprintf(stderr, "go-schema doc: unknown format %q\n", *format)
+		return exitError
+	}
+
+	inputs, err := readInputs(fs.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema doc: %v\n", err)
+		return exitError
+	}
+
+	// The documented schemas are registered too, so that they may reference
+	// each other and themselves
+	registry := validator.NewRegistry()
+	for _, path := range includes {
+		documented := false
+		for _, in := range inputs {
+			documented = documented || samePath(path, in.name)
+		}
+		if documented {
+			continue
+		}
+		if _, err := registry.LoadSchemaFile(path); err != nil {
+			fmt.Fprintf(stderr, "go-schema doc: %v\n", err)
+			return exitError
+		}
+	}
+	schemas := make([]validator.Schema, len(inputs))
+	for i, in := range inputs {
+		schemas[i], err = registry.LoadSchema(schemaName(in.name), bytes.NewReader(in.data))
+		if err != nil {
+			fmt.Fprintf(stderr, "go-schema doc: %s: %v\n", in.name, err)
+			return exitError
+		}
+	}
+
+	// Every schema gets a heading once there is more than one.
+	var b strings.Builder
+	for i, in := range inputs {
+		schema := schemas[i]
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if *format == "html" {
+			if len(inputs) > 1 {
+				fmt.Fprintf(&b, "<h2>%s</h2>\n", html.EscapeString(schemaName(in.name)))
+			}
+			b.WriteString(registry.RenderHTML(schema))
+			continue
+		}
+		if len(inputs) > 1 {
+			fmt.Fprintf(&b, "## %s\n\n", schemaName(in.name))
+		}
+		b.WriteString(registry.RenderMarkdown(schema))
+	}
+	if _, err := io.WriteString(stdout, b.String()); err != nil {
+		fmt.Fprintf(stderr, "go-schema doc: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocCommand(t *testing.T) {
+	code, out, _ := runCLI(testSchema, "doc")
+	want := "| Field | Type | Required | Default | Constraints | Description |\n" +
+		"|-------|------|----------|---------|-------------|-------------|\n" +
+		"| `age` | int | no |  | min 18 |  |\n" +
+		"| `name` | string | yes |  | min length 2 |  |\n"
+	if code != exitOK || out != want {
+		t.Errorf("code %d, output:\n%s", code, out)
+	}
+
+	user := writeFile(t, "user.json", testSchema)
+	tag := writeFile(t, "tag.json", `{"label": {"type": "string"}}`)
+	code, out, _ = runCLI("", "doc", "--format", "html", user, tag)
+	if code != exitOK || !strings.HasPrefix(out, "<h2>user</h2>\n<table>") || !strings.Contains(out, "</table>\n\n<h2>tag</h2>\n<table>") {
+		t.Errorf("html: code %d, output:\n%s", code, out)
+	}
+
+	if code, _, _ := runCLI(testSchema, "doc", "--format", "pdf"); code != exitError {
+		t.Errorf("Expected exit code %d for an unknown format, got %d", exitError, code)
+	}
+	if code, _, _ := runCLI(`{"a": `, "doc"); code != exitError {
+		t.Errorf("Expected exit code %d for a bad schema, got %d", exitError, code)
+	}
+}
+
+func TestDocCommandInclude(t *testing.T) {
+	address := writeFile(t, "address.json", `{"zip": {"type": "string", "required": true}}`)
+	user := writeFile(t, "user.json", `{"home": {"ref": "address"}, "friends": {"type": "list", "list": {"ref": "user"}}}`)
+
+	code, out, stderr := runCLI("", "doc", "--include", address, "--include", user, user)
+	for _, want := range []string{
+		"| `home` | map (address) | no |  |  |  |\n| `home.zip` | string | yes |",
+		"| `friends[]` | map (user) |  |  |  |  |\n| `friends[].friends` | list | no |",
+		"| `friends[].friends[]` | ref user |",
+	} {
+		if code != exitOK || !strings.Contains(out, want) {
+			t.Errorf("Expected %q, got code %d, output:\n%s%s", want, code, out, stderr)
+		}
+	}
+
+	code, out, _ = runCLI("", "doc", user)
+	if code != exitOK || !strings.Contains(out, "| `home` | ref address | no |") {
+		t.Errorf("Expected an unexpanded ref without --include, got code %d, output:\n%s", code, out)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/josesalasdev/go-schema/validator"
+)
+
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	write := fs.Bool("w", false, "write the result back to the source file instead of stdout")
+	list := fs.Bool("l", false, "list files whose formatting differs from the canonical form")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	inputs, err := readInputs(fs.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema fmt: %v\n", err)
+		return exitError
+	}
+
+	code := exitOK
+	for _, in := range inputs {
+		schema, err := validator.ParseSchema(in.data)
+		if err != nil {
+			fmt.Fprintf(stderr, "go-schema fmt: %s: %v\n", in.name, err)
+			code = exitError
+			continue
+		}
+		out, err := validator.MarshalSchema(schema)
+		if err != nil {
+			fmt.Fprintf(stderr, "go-schema fmt: %s: %v\n", in.name, err)
+			code = exitError
+			continue
+		}
+
+		changed := !bytes.Equal(in.data, out)
+		switch {
+		case *list:
+			if changed {
+				fmt.Fprintln(stdout, in.name)
+			}
+		case *write && in.name != "<stdin>":
+			if changed {
+				if err := os.WriteFile(in.name, out, 0o644); err != nil {
+					fmt.Fprintf(stderr, "go-schema fmt: %v\n", err)
+					code = exitError
+				}
+			}
+		default:
+			if _, err := stdout.Write(out); err != nil {
+				fmt.Fprintf(stderr, "go-schema fmt: %v\n", err)
+				return exitError
+			}
+		}
+	}
+	return code
+}
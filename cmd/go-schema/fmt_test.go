@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+const canonicalSchema = `{
+  "age": {
+    "type": "int",
+    "min": 18
+  },
+  "name": {
+    "type": "string",
+    "required": true,
+    "min_length": 2
+  }
+}
+`
+
+func TestFmtCommand(t *testing.T) {
+	code, out, _ := runCLI(testSchema, "fmt")
+	if code != exitOK || out != canonicalSchema {
+		t.Errorf("code %d, output:\n%s", code, out)
+	}
+
+	messy := writeFile(t, "messy.json", testSchema)
+	clean := writeFile(t, "clean.json", canonicalSchema)
+
+	code, out, _ = runCLI("", "fmt", "-l", messy, clean)
+	if code != exitOK || out != messy+"\n" {
+		t.Errorf("-l: code %d, output %q", code, out)
+	}
+
+	code, out, _ = runCLI("", "fmt", "-w", messy)
+	if code != exitOK || out != "" {
+		t.Errorf("-w: code %d, output %q", code, out)
+	}
+	if data, _ := os.ReadFile(messy); string(data) != canonicalSchema {
+		t.Errorf("-w did not rewrite the file:\n%s", data)
+	}
+
+	code, _, stderr := runCLI(`{"age": {"type": "int", "bogus": 1}}`, "fmt")
+	if code != exitError || stderr == "" {
+		t.Errorf("Expected error for invalid schema, got code %d", code)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/josesalasdev/go-schema/validator"
+)
+
+func runGen(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "structs" {
+		fmt.Fprintln(stderr, "usage: go-schema gen structs [--package name] [--type Name] [-o file.go] [schema.json ...]")
+		return exitError
+	}
+
+	fs := flag.NewFlagSet("gen structs", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	pkg := fs.String("package", os.Getenv("GOPACKAGE"), "package of the generated file (default $GOPACKAGE, set by go:generate)")
+	typeName := fs.String("type", "", "name of the generated type, for a single schema (default: file name in CamelCase)")
+	output := fs.String("o", "", "write to this file instead of stdout")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitError
+	}
+	if *pkg == "" {
+		fmt.Fprintln(stderr, "go-schema gen structs: --package is required outside go:generate")
+		return exitError
+	}
+	if *typeName != "" && fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "go-schema gen structs: --type needs a single schema file")
+		return exitError
+	}
+
+	inputs, err := readInputs(fs.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema gen structs: %v\n", err)
+		return exitError
+	}
+	types := make([]validator.StructType, len(inputs))
+	for i, in := range inputs {
+		schema, err := validator.ParseSchema(in.data)
+		if err != nil {
+			fmt.Fprintf(stderr, "go-schema gen structs: %s: %v\n", in.name, err)
+			return exitError
+		}
+		name := *typeName
+		if name == "" {
+			name = typeNameOf(schemaName(in.name))
+		}
+		types[i] = validator.StructType{Name: name, Schema: schema}
+	}
+
+	src, err := validator.GenerateStructs(*pkg, types)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema gen structs: %v\n", err)
+		return exitError
+	}
+	if *output != "" {
+		err = os.WriteFile(*output, src, 0o644)
+	} else {
+		_, err = stdout.Write(src)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema gen structs: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// typeNameOf turns a schema file name such as "order_line" into a type name
+// such as "OrderLine".
+func typeNameOf(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
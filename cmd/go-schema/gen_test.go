@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenStructsCommand(t *testing.T) {
+	schema := writeFile(t, "order_line.json", `{"sku": {"type": "string", "required": true}, "qty": {"type": "int"}}`)
+
+	code, out, _ := runCLI("", "gen", "structs", "--package", "models", schema)
+	if code != exitOK || !strings.Contains(out, "package models") || !strings.Contains(out, "type OrderLine struct {") {
+		t.Errorf("code %d, output:\n%s", code, out)
+	}
+
+	dest := filepath.Join(t.TempDir(), "models.go")
+	t.Setenv("GOPACKAGE", "gen")
+	code, out, _ = runCLI(`{"id": {"type": "int"}}`, "gen", "structs", "--type", "Item", "-o", dest)
+	data, _ := os.ReadFile(dest)
+	if code != exitOK || out != "" || !strings.Contains(string(data), "package gen") || !strings.Contains(string(data), "type Item struct {") {
+		t.Errorf("-o: code %d, output %q, file:\n%s", code, out, data)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Missing subcommand", args: []string{"gen"}},
+		{name: "Unknown subcommand", args: []string{"gen", "enums", schema}},
+		{name: "Type with many files", args: []string{"gen", "structs", "--type", "X", schema, schema}},
+		{name: "Bad type name", args: []string{"gen", "structs", "--type", "lower", schema}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, _ := runCLI("", tt.args...); code != exitError {
+				t.Errorf("Expected exit code %d, got %d", exitError, code)
+			}
+		})
+	}
+}
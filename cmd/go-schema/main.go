@@ -0,0 +1,103 @@
+// Command go-schema validates JSON documents against go-schema schemas and
+// helps maintain the schema files themselves.
+//
+// Usage:
+//
+//	go-schema validate --schema schema.json [--include ref.json ...] [--format text|json|junit] [data.json ...]
+//	go-schema check schema.json ...
+//	go-schema fmt [-w] [-l] [schema.json ...]
+//	go-schema doc [--format markdown|html] [--include schema.json ...] [schema.json ...]
+//	go-schema gen structs [--package name] [--type Name] [-o file.go] [schema.json ...]
+//
+// Commands that take files read from standard input when no file, or "-",
+// is given. Schema files can reference each other through "ref" using their
+// base name without extension.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes shared by all commands.
+const (
+	exitOK      = 0
+	exitInvalid = 1 // Data or schema did not pass validation
+	exitError   = 2 // Usage, I/O or parse error
+)
+
+const usage = `usage: go-schema <command> [flags] [files]
+
+commands:
+  validate  validate JSON data files against a schema
+  check     check schema files for mistakes
+  fmt       format schema files canonically
+  doc       document schema files as Markdown or HTML tables
+  gen       generate Go structs from schema files ("gen structs")
+
+Run "go-schema <command> -h" for the flags of a command.
+`
+
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"validate": runValidate,
+	"check":    runCheck,
+	"fmt":      runFmt,
+	"doc":      runDoc,
+	"gen":      runGen,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "go-schema: unknown command %q\n\n%s", args[0], usage)
+		return exitError
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+// input is a named source of bytes given on the command line.
+type input struct {
+	name string
+	data []byte
+}
+
+// readInputs reads every named file, or stdin when names is empty or a name
+// is "-".
+func readInputs(names []string, stdin io.Reader) ([]input, error) {
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+
+	inputs := make([]input, 0, len(names))
+	for _, name := range names {
+		var data []byte
+		var err error
+		if name == "-" {
+			name = "<stdin>"
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input{name: name, data: data})
+	}
+	return inputs, nil
+}
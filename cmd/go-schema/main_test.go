@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+  "name": {"type": "string", "required": true, "min_length": 2},
+  "age": {"type": "int", "min": 18}
+}`
+
+// writeFile creates a file with the given content in a temporary directory.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// runCLI runs the command line with the given stdin and returns exit code
+// and captured output.
+func runCLI(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunDispatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{name: "No command", args: nil, wantCode: exitError},
+		{name: "Help", args: []string{"help"}, wantCode: exitOK},
+		{name: "Unknown command", args: []string{"frobnicate"}, wantCode: exitError},
+		{name: "Bad flag", args: []string{"check", "--nope"}, wantCode: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := runCLI("", tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestReadInputsStdin(t *testing.T) {
+	inputs, err := readInputs([]string{"-"}, strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) != 1 || inputs[0].name != "<stdin>" || string(inputs[0].data) != "{}" {
+		t.Errorf("unexpected inputs: %+v", inputs)
+	}
+
+	if _, err := readInputs([]string{filepath.Join(t.TempDir(), "missing")}, nil); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
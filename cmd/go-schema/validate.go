@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/josesalasdev/go-schema/validator"
+)
+
+// fileResult is the outcome of validating one data file.
+type fileResult struct {
+	File     string                      `json:"file"`
+	Valid    bool                        `json:"valid"`
+	Errors   []validator.ValidationError `json:"errors"`
+	Warnings []validator.ValidationError `json:"warnings,omitempty"`
+}
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	schemaPath := fs.String("schema", "", "path to the schema JSON file (required)")
+	format := fs.String("format", "text", "output format: text, json or junit")
+	strict := fs.Bool("strict", false, "report warnings, such as deprecated fields, as errors")
+	var includes []string
+	fs.Func("include", "schema file that --schema may reference by base name (repeatable)", func(path string) error {
+		includes = append(includes, path)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	if *schemaPath == "" {
+		fmt.Fprintln(stderr, "go-schema validate: --schema is required")
+		return exitError
+	}
+	write, ok := reporters[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "go-schema validate: unknown format %q\n", *format)
+		return exitError
+	}
+
+	// The schema is registered too, so that it may reference itself
+	registry := validator.NewRegistry()
+	for _, path := range includes {
+		if samePath(path, *schemaPath) {
+			continue
+		}
+		if _, err := registry.LoadSchemaFile(path); err != nil {
+			fmt.Fprintf(stderr, "go-schema validate: %v\n", err)
+			return exitError
+		}
+	}
+	schema, err := registry.LoadSchemaFile(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema validate: %v\n", err)
+		return exitError
+	}
+	inputs, err := readInputs(fs.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-schema validate: %v\n", err)
+		return exitError
+	}
+
+	v := validator.New(validator.WithRegistry(registry), validator.WithPromoteWarnings(*strict))
+	results := make([]fileResult, 0, len(inputs))
+	for _, in := range inputs {
+		var data map[string]interface{}
+		if err := json.Unmarshal(in.data, &data); err != nil || data == nil {
+			if err == nil {
+				err = fmt.Errorf("document is not a JSON object")
+			}
+			fmt.Fprintf(stderr, "go-schema validate: %s: %v\n", in.name, err)
+			return exitError
+		}
+
+		result := v.Validate(data, schema)
+		sortErrors(result.Errors)
+		sortErrors(result.Warnings)
+		results = append(results, fileResult{File: in.name, Valid: result.IsValid, Errors: result.Errors, Warnings: result.Warnings})
+	}
+
+	if err := write(stdout, *schemaPath, results); err != nil {
+		fmt.Fprintf(stderr, "go-schema validate: %v\n", err)
+		return exitError
+	}
+	for _, r := range results {
+		if !r.Valid {
+			return exitInvalid
+		}
+	}
+	return exitOK
+}
+
+// samePath reports whether a and b name the same file.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// sortErrors orders errors by field and message so that output does not
+// depend on map iteration order.
+func sortErrors(errs []validator.ValidationError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Field != errs[j].Field {
+			return errs[i].Field < errs[j].Field
+		}
+		return errs[i].Message < errs[j].Message
+	})
+}
+
+type reporter func(w io.Writer, schemaPath string, results []fileResult) error
+
+var reporters = map[string]reporter{
+	"text":  writeText,
+	"json":  writeJSON,
+	"junit": writeJUnit,
+}
+
+func writeText(w io.Writer, _ string, results []fileResult) error {
+	var buf bytes.Buffer
+	for _, r := range results {
+		for _, e := range r.Warnings {
+			fmt.Fprintf(&buf, "%s: warning: %s\n", r.File, e.Error())
+		}
+		if r.Valid {
+			fmt.Fprintf(&buf, "%s: ok\n", r.File)
+			continue
+		}
+		for _, e := range r.Errors {
+			fmt.Fprintf(&buf, "%s: %s\n", r.File, e.Error())
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeJSON(w io.Writer, _ string, results []fileResult) error {
+	for i := range results {
+		if results[i].Errors == nil {
+			results[i].Errors = []validator.ValidationError{}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, schemaPath string, results []fileResult) error {
+	suite := junitSuite{Name: "go-schema", Tests: len(results)}
+	for _, r := range results {
+		tc := junitCase{Name: r.File, ClassName: schemaPath}
+		if !r.Valid {
+			suite.Failures++
+			var text bytes.Buffer
+			for _, e := range r.Errors {
+				fmt.Fprintln(&text, e.Error())
+			}
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d validation error(s)", len(r.Errors)),
+				Text:    text.String(),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
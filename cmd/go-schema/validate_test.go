@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	schema := writeFile(t, "schema.json", testSchema)
+	valid := writeFile(t, "valid.json", `{"name": "Ann", "age": 30}`)
+	invalid := writeFile(t, "invalid.json", `{"age": 12}`)
+
+	code, out, _ := runCLI("", "validate", "--schema", schema, valid)
+	if code != exitOK || !strings.Contains(out, "valid.json: ok") {
+		t.Errorf("valid file: code %d, output %q", code, out)
+	}
+
+	code, out, _ = runCLI("", "validate", "--schema", schema, valid, invalid)
+	if code != exitInvalid {
+		t.Errorf("Expected exit code %d, got %d", exitInvalid, code)
+	}
+	wantLines := []string{
+		valid + ": ok",
+		invalid + ": age: Value 12 is less than minimum 18",
+		invalid + ": name: Field is required",
+	}
+	if got := strings.Split(strings.TrimSpace(out), "\n"); strings.Join(got, "\n") != strings.Join(wantLines, "\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestValidateCommandStdin(t *testing.T) {
+	schema := writeFile(t, "schema.json", testSchema)
+
+	code, out, _ := runCLI(`{"name": "Bo"}`, "validate", "--schema", schema)
+	if code != exitOK || out != "<stdin>: ok\n" {
+		t.Errorf("code %d, output %q", code, out)
+	}
+}
+
+func TestValidateCommandFormats(t *testing.T) {
+	schema := writeFile(t, "schema.json", testSchema)
+
+	code, out, _ := runCLI(`{"name": "B"}`, "validate", "--schema", schema, "--format", "json", "-")
+	if code != exitInvalid {
+		t.Errorf("Expected exit code %d, got %d", exitInvalid, code)
+	}
+	var results []fileResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(results) != 1 || results[0].Valid || results[0].Errors[0].Field != "name" {
+		t.Errorf("unexpected JSON results: %+v", results)
+	}
+
+	_, out, _ = runCLI(`{"name": "B"}`, "validate", "--schema", schema, "--format", "junit")
+	for _, want := range []string{`<testsuite name="go-schema" tests="1" failures="1">`, `<failure message="1 validation error(s)">`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("JUnit output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestValidateCommandErrors(t *testing.T) {
+	schema := writeFile(t, "schema.json", testSchema)
+	badSchema := writeFile(t, "bad.json", `{"name": {"type": "string", "regex": "(["}}`)
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+	}{
+		{name: "Missing schema flag", args: []string{"validate"}},
+		{name: "Unknown format", args: []string{"validate", "--schema", schema, "--format", "xml"}},
+		{name: "Unreadable schema", args: []string{"validate", "--schema", badSchema}},
+		{name: "Malformed data", stdin: `{"name":`, args: []string{"validate", "--schema", schema}},
+		{name: "Non-object data", stdin: `[1, 2]`, args: []string{"validate", "--schema", schema}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(tt.stdin, tt.args...)
+			if code != exitError || stderr == "" {
+				t.Errorf("Expected exit code %d with a message, got %d (%q)", exitError, code, stderr)
+			}
+		})
+	}
+}
+
+func TestValidateCommandInclude(t *testing.T) {
+	address := writeFile(t, "address.json", `{"zip": {"type": "string", "required": true}}`)
+	schema := writeFile(t, "user.json", `{"home": {"ref": "address", "required": true}}`)
+
+	code, out, _ := runCLI(`{"home": {}}`, "validate", "--schema", schema, "--include", address)
+	if code != exitInvalid || out != "<stdin>: home.zip: Field is required\n" {
+		t.Errorf("code %d, output %q", code, out)
+	}
+
+	code, out, _ = runCLI(`{"home": {}}`, "validate", "--schema", schema)
+	if code != exitInvalid || !strings.Contains(out, "unresolved reference 'address'") {
+		t.Errorf("Expected unresolved reference without --include, got code %d, output %q", code, out)
+	}
+}
+
+func TestValidateCommandRecursiveSchema(t *testing.T) {
+	schema := writeFile(t, "comment.json", `{
+		"text": {"type": "string", "required": true},
+		"replies": {"type": "list", "list": {"ref": "comment"}}
+	}`)
+	data := `{"text": "hi", "replies": [{"text": "hello", "replies": [{}]}]}`
+
+	for _, args := range [][]string{
+		{"validate", "--schema", schema},
+		{"validate", "--schema", schema, "--include", schema},
+	} {
+		code, out, stderr := runCLI(data, args...)
+		if code != exitInvalid || out != "<stdin>: replies[0].replies[0].text: Field is required\n" {
+			t.Errorf("%v: code %d, output %q, stderr %q", args, code, out, stderr)
+		}
+	}
+}
+
+func TestValidateCommandWarnings(t *testing.T) {
+	schema := writeFile(t, "schema.json", `{
+		"name": {"type": "string", "required": true},
+		"fax": {"type": "string", "deprecated": "use phone"}
+	}`)
+	data := `{"name": "Ann", "fax": "555"}`
+
+	code, out, _ := runCLI(data, "validate", "--schema", schema)
+	if code != exitOK || out != "<stdin>: warning: fax: Field is deprecated: use phone\n<stdin>: ok\n" {
+		t.Errorf("code %d, output %q", code, out)
+	}
+
+	code, out, _ = runCLI(data, "validate", "--schema", schema, "--strict")
+	if code != exitInvalid || out != "<stdin>: fax: Field is deprecated: use phone\n" {
+		t.Errorf("strict: code %d, output %q", code, out)
+	}
+}
@@ -0,0 +1,393 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// StructType names a schema for GenerateStructs.
+type StructType struct {
+	Name   string // Name of the Go type, e.g. "User"
+	Schema Schema
+}
+
+// GenerateStructs returns the gofmt-formatted source of a Go file in package
+// pkg declaring a struct for each of types, with json tags matching the
+// schema field names. Required fields have plain types and optional ones
+// pointers, except slices and maps; nested Schema rules become struct types
+// named after their parent and field, e.g. UserAddress, and List rules
+// become slices. Refs and custom types become interface{}.
+//
+// Every struct also gets FromMap, which fills it from data that passed
+// Validate, and ToMap, which converts it back. The conversion functions
+// FromMap uses are named after the first type, e.g. schemaUserString, so
+// that files generated for different types can share a package. The output
+// is deterministic, so it can be produced by go:generate and checked in.
+func GenerateStructs(pkg string, types []StructType) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	cg := &codegen{used: map[string]bool{}, helpers: map[string]bool{}}
+	if len(types) > 0 {
+		cg.prefix = "schema" + types[0].Name
+	}
+	for _, t := range types {
+		if !token.IsIdentifier(t.Name) || !token.IsExported(t.Name) {
+			return nil, fmt.Errorf("invalid type name %q: must be an exported Go identifier", t.Name)
+		}
+		if cg.used[t.Name] {
+			return nil, fmt.Errorf("duplicate type name %q", t.Name)
+		}
+		cg.used[t.Name] = true
+	}
+	for _, t := range types {
+		cg.structType(t.Name, t.Schema, fmt.Sprintf("%s is generated from a go-schema schema.", t.Name))
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by go-schema; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	if cg.helpers["fmt"] {
+		out.WriteString("import \"fmt\"\n\n")
+	}
+	out.Write(cg.body.Bytes())
+	names := make([]string, 0, len(cg.helpers))
+	for name := range cg.helpers {
+		if name != "fmt" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.WriteString(strings.ReplaceAll(codegenHelpers[name], "NAME", cg.prefix+name))
+	}
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go source: %v", err)
+	}
+	return src, nil
+}
+
+// codegen holds the state of one GenerateStructs call.
+type codegen struct {
+	body    bytes.Buffer
+	used    map[string]bool // Type names taken so far
+	helpers map[string]bool // Conversion helpers used, and "fmt"
+	prefix  string          // Prefix of the helper names
+}
+
+// structField is one field of a generated struct.
+type structField struct {
+	key    string // Key in the map
+	name   string // Go field name
+	rule   Rule
+	goType string
+	ptr    bool // Optional field held as a pointer
+}
+
+// structType writes the struct for schema and its methods, after the types
+// of its nested schemas.
+func (cg *codegen) structType(name string, schema Schema, doc string) {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Field names must not clash with the generated methods
+	fieldNames := map[string]bool{"FromMap": true, "ToMap": true}
+	var fields []structField
+	for _, key := range keys {
+		rule := schema[key]
+		f := structField{key: key, name: uniqueName(goName(key), fieldNames), rule: rule}
+		f.goType = cg.goType(rule, name+f.name, fmt.Sprintf("the %q field of %s", key, name))
+		f.ptr = !rule.Required && !strings.HasPrefix(f.goType, "[]") &&
+			!strings.HasPrefix(f.goType, "map[") && f.goType != "interface{}"
+		fields = append(fields, f)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\ntype %s struct {\n", doc, name)
+	for _, f := range fields {
+		text := strings.TrimSpace(f.rule.Title + "\n" + f.rule.Description)
+		if text != "" {
+			for _, line := range strings.Split(text, "\n") {
+				fmt.Fprintf(&b, "// %s\n", line)
+			}
+		}
+		if f.rule.Deprecated != "" {
+			if text != "" {
+				b.WriteString("//\n")
+			}
+			fmt.Fprintf(&b, "// Deprecated: %s\n", f.rule.Deprecated)
+		}
+		typ, tag := f.goType, f.key
+		if f.ptr {
+			typ = "*" + typ
+		}
+		if !f.rule.Required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "%s %s `json:%s`\n", f.name, typ, strconv.Quote(tag))
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// FromMap sets x from m, which should have passed validation.\nfunc (x *%s) FromMap(m map[string]interface{}) error {\n", name)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "if v, ok := m[%s]; ok && v != nil {\n", strconv.Quote(f.key))
+		dst := "x." + f.name
+		cg.from(&b, f.rule, f.goType, "v", dst, f.ptr, strings.ReplaceAll(f.key, "%", "%%"), nil, 0)
+		b.WriteString("}\n")
+	}
+	b.WriteString("return nil\n}\n\n")
+
+	fmt.Fprintf(&b, "// ToMap converts x to the map form that validation checks.\nfunc (x %s) ToMap() map[string]interface{} {\nm := map[string]interface{}{}\n", name)
+	for _, f := range fields {
+		src := "x." + f.name
+		key := strconv.Quote(f.key)
+		switch {
+		case f.ptr:
+			fmt.Fprintf(&b, "if %s != nil {\n", src)
+			cg.to(&b, f.rule, "*"+src, "m["+key+"]", 0, false)
+			b.WriteString("}\n")
+		case !f.rule.Required:
+			fmt.Fprintf(&b, "if %s != nil {\n", src)
+			cg.to(&b, f.rule, src, "m["+key+"]", 0, false)
+			b.WriteString("}\n")
+		default:
+			cg.to(&b, f.rule, src, "m["+key+"]", 0, true)
+		}
+	}
+	b.WriteString("return m\n}\n\n")
+
+	cg.body.Write(b.Bytes())
+}
+
+// goType returns the Go type for rule, declaring a struct named name for a
+// nested schema, documented as holding what.
+func (cg *codegen) goType(rule Rule, name, what string) string {
+	if rule.Ref != "" {
+		return "interface{}"
+	}
+	switch rule.Type {
+	case "string":
+		return "string"
+	case "int":
+		return "int"
+	case "float":
+		return "float64"
+	case "bool":
+		return "bool"
+	case "list":
+		if rule.List == nil {
+			return "[]interface{}"
+		}
+		return "[]" + cg.goType(*rule.List, name+"Item", "an item of "+what)
+	case "map":
+		if rule.Schema == nil {
+			return "map[string]interface{}"
+		}
+		name = uniqueName(name, cg.used)
+		cg.structType(name, *rule.Schema, fmt.Sprintf("%s is %s.", name, what))
+		return name
+	}
+	return "interface{}"
+}
+
+// from writes code converting the value in the variable src to goType and
+// storing it in dst. Errors are prefixed with the value's path, given as a
+// fmt format and the variables holding its list indices.
+func (cg *codegen) from(b *bytes.Buffer, rule Rule, goType, src, dst string, ptr bool, path string, indices []string, depth int) {
+	errorf := func(format string) string {
+		return strings.Join(append(append([]string{strconv.Quote(path + format)}, indices...), "err"), ", ")
+	}
+	store := func(value string) {
+		if ptr {
+			fmt.Fprintf(b, "%s = &%s\n", dst, value)
+		} else {
+			fmt.Fprintf(b, "%s = %s\n", dst, value)
+		}
+	}
+	convert := func(helper, value string) {
+		cg.helpers[helper] = true
+		cg.helpers["fmt"] = true
+		fmt.Fprintf(b, "%s, err := %s(%s)\nif err != nil {\nreturn fmt.Errorf(%s)\n}\n", value, cg.prefix+helper, src, errorf(": %w"))
+	}
+
+	switch {
+	case goType == "interface{}":
+		store(src)
+	case goType == "string", goType == "int", goType == "float64", goType == "bool":
+		value := fmt.Sprintf("val%d", depth)
+		convert(scalarHelpers[goType], value)
+		store(value)
+	case goType == "map[string]interface{}":
+		value := fmt.Sprintf("obj%d", depth)
+		convert("Map", value)
+		store(value)
+	case strings.HasPrefix(goType, "[]"):
+		items, i, item := fmt.Sprintf("items%d", depth), fmt.Sprintf("i%d", depth), fmt.Sprintf("item%d", depth)
+		convert("List", items)
+		fmt.Fprintf(b, "%s = make(%s, len(%s))\nfor %s, %s := range %s {\n", dst, goType, items, i, item, items)
+		if rule.List != nil {
+			cg.from(b, *rule.List, goType[2:], item, dst+"["+i+"]", false, path+"[%d]", append(append([]string{}, indices...), i), depth+1)
+		} else {
+			fmt.Fprintf(b, "%s[%s] = %s\n", dst, i, item)
+		}
+		b.WriteString("}\n")
+	default:
+		obj, value := fmt.Sprintf("obj%d", depth), fmt.Sprintf("val%d", depth)
+		convert("Map", obj)
+		fmt.Fprintf(b, "var %s %s\nif err := %s.FromMap(%s); err != nil {\nreturn fmt.Errorf(%s)\n}\n", value, goType, value, obj, errorf(".%w"))
+		store(value)
+	}
+}
+
+// scalarHelpers name the helper converting to each scalar Go type.
+var scalarHelpers = map[string]string{
+	"string": "String", "int": "Int", "float64": "Float", "bool": "Bool",
+}
+
+// to writes code converting the Go value src back to its map form and
+// storing it in dst. Temporary variables go in a block of their own if
+// block is set, as they would otherwise clash between fields.
+func (cg *codegen) to(b *bytes.Buffer, rule Rule, src, dst string, depth int, block bool) {
+	switch {
+	case rule.Ref != "":
+		fmt.Fprintf(b, "%s = %s\n", dst, src)
+	case rule.Type == "list" && rule.List != nil:
+		list, i, item := fmt.Sprintf("list%d", depth), fmt.Sprintf("i%d", depth), fmt.Sprintf("item%d", depth)
+		if block {
+			b.WriteString("{\n")
+		}
+		fmt.Fprintf(b, "%s := make([]interface{}, len(%s))\nfor %s, %s := range %s {\n", list, src, i, item, src)
+		cg.to(b, *rule.List, item, list+"["+i+"]", depth+1, false)
+		fmt.Fprintf(b, "}\n%s = %s\n", dst, list)
+		if block {
+			b.WriteString("}\n")
+		}
+	case rule.Type == "map" && rule.Schema != nil:
+		fmt.Fprintf(b, "%s = %s.ToMap()\n", dst, strings.TrimPrefix(src, "*"))
+	default:
+		fmt.Fprintf(b, "%s = %s\n", dst, src)
+	}
+}
+
+// goName turns a schema field name such as "user_id" into an exported Go
+// identifier such as "UserID".
+func goName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+		name = "F" + name
+	}
+	return name
+}
+
+// commonInitialisms are written in capitals in Go names, as golint does.
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URI": true, "URL": true, "UUID": true,
+}
+
+// uniqueName returns name, or name followed by a number if it is taken.
+func uniqueName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// codegenHelpers are the conversion functions generated code may use, with
+// NAME standing for their prefixed name.
+var codegenHelpers = map[string]string{
+	"String": `
+// NAME converts a string value.
+func NAME(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("expected string, got %T", v)
+}
+`,
+	"Int": `
+// NAME converts an int value, which JSON decodes as float64.
+func NAME(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("expected int, got %T", v)
+}
+`,
+	"Float": `
+// NAME converts a float value.
+func NAME(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	}
+	return 0, fmt.Errorf("expected float, got %T", v)
+}
+`,
+	"Bool": `
+// NAME converts a bool value.
+func NAME(v interface{}) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	return false, fmt.Errorf("expected bool, got %T", v)
+}
+`,
+	"List": `
+// NAME converts a list value.
+func NAME(v interface{}) ([]interface{}, error) {
+	if l, ok := v.([]interface{}); ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("expected list, got %T", v)
+}
+`,
+	"Map": `
+// NAME converts a map value.
+func NAME(v interface{}) (map[string]interface{}, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("expected map, got %T", v)
+}
+`,
+}
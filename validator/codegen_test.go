@@ -0,0 +1,150 @@
+package validator
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestGenerateStructs(t *testing.T) {
+	schema := Schema{
+		"user_id": {Type: "int", Required: true},
+		"name":    {Type: "string", Required: true, Description: "Full name"},
+		"tags":    {Type: "list", List: &Rule{Type: "string"}},
+		"address": {Type: "map", Schema: &Schema{"zip": {Type: "string", Required: true}}},
+	}
+	src, err := GenerateStructs("models", []StructType{{Name: "User", Schema: schema}})
+	if err != nil {
+		t.Fatalf("GenerateStructs returned error: %v", err)
+	}
+
+	for _, want := range []string{
+		"// Code generated by go-schema; DO NOT EDIT.\n\npackage models\n",
+		"type UserAddress struct {\n\tZip string `json:\"zip\"`\n}",
+		"type User struct {\n" +
+			"\tAddress *UserAddress `json:\"address,omitempty\"`\n" +
+			"\t// Full name\n" +
+			"\tName   string   `json:\"name\"`\n" +
+			"\tTags   []string `json:\"tags,omitempty\"`\n" +
+			"\tUserID int      `json:\"user_id\"`\n}",
+		"func (x *User) FromMap(m map[string]interface{}) error {",
+		"func (x User) ToMap() map[string]interface{} {",
+		"\t\treturn fmt.Errorf(\"address.%w\", err)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("output missing %q:\n%s", want, src)
+		}
+	}
+	if !strings.Contains(string(src), "func schemaUserString(") || strings.Contains(string(src), "func schemaUserBool(") {
+		t.Errorf("Expected only the used helpers, named after User:\n%s", src)
+	}
+
+	if formatted, err := format.Source(src); err != nil || string(formatted) != string(src) {
+		t.Errorf("Expected gofmt-clean output, err %v", err)
+	}
+	again, _ := GenerateStructs("models", []StructType{{Name: "User", Schema: schema}})
+	if string(again) != string(src) {
+		t.Errorf("Expected deterministic output")
+	}
+}
+
+func TestGenerateStructsCompiles(t *testing.T) {
+	schema := Schema{
+		"id":       {Type: "int", Required: true},
+		"score":    {Type: "float"},
+		"active":   {Type: "bool", Deprecated: "always true"},
+		"matrix":   {Type: "list", Required: true, List: &Rule{Type: "list", List: &Rule{Type: "int"}}},
+		"meta":     {Type: "map"},
+		"any":      {Type: "list"},
+		"owner":    {Ref: "user"},
+		"custom":   {Type: "money"},
+		"orders":   {Type: "list", List: &Rule{Type: "map", Schema: &Schema{"sku": {Type: "string"}}}},
+		"order":    {Type: "map", Required: true, Schema: &Schema{"id": {Type: "int"}}},
+		"2fa-key":  {Type: "string"},
+		"from_map": {Type: "string"},
+		"to_map":   {Type: "int", Required: true},
+		"50%\"off": {Type: "bool"},
+	}
+	src, err := GenerateStructs("models", []StructType{{Name: "Account", Schema: schema}, {Name: "Empty", Schema: Schema{}}})
+	if err != nil {
+		t.Fatalf("GenerateStructs returned error: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "models.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("models", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("generated code does not type-check: %v\n%s", err, src)
+	}
+	for _, name := range []string{"Account", "AccountOrder", "AccountOrdersItem", "Empty"} {
+		if pkg.Scope().Lookup(name) == nil {
+			t.Errorf("Expected type %s in:\n%s", name, src)
+		}
+	}
+	for _, want := range []string{
+		"F2faKey *string `json:\"2fa-key,omitempty\"`",
+		"FromMap2 *string                `json:\"from_map,omitempty\"`",
+		"ToMap2   int                    `json:\"to_map\"`",
+		`fmt.Errorf("orders[%d].%w", i0, err)`,
+		`fmt.Errorf("matrix[%d][%d]: %w", i0, i1, err)`,
+		`fmt.Errorf("50%%\"off: %w", err)`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected %s in:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateStructsSharedPackage(t *testing.T) {
+	schema := Schema{
+		"id":    {Type: "int", Required: true},
+		"name":  {Type: "string"},
+		"tags":  {Type: "list", List: &Rule{Type: "string"}},
+		"extra": {Type: "map"},
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range []string{"User", "Order"} {
+		src, err := GenerateStructs("models", []StructType{{Name: name, Schema: schema}})
+		if err != nil {
+			t.Fatalf("GenerateStructs returned error: %v", err)
+		}
+		file, err := parser.ParseFile(fset, strings.ToLower(name)+".go", src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("generated code does not parse: %v\n%s", err, src)
+		}
+		files = append(files, file)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("models", fset, files, nil); err != nil {
+		t.Fatalf("files generated separately do not type-check in one package: %v", err)
+	}
+}
+
+func TestGenerateStructsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		pkg   string
+		types []StructType
+	}{
+		{"Bad package", "my-models", nil},
+		{"Unexported type", "models", []StructType{{Name: "user"}}},
+		{"Duplicate type", "models", []StructType{{Name: "User"}, {Name: "User"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateStructs(tt.pkg, tt.types); err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+	}
+}
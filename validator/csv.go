@@ -0,0 +1,237 @@
+package validator
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions configures ValidateCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter. It defaults to ','.
+	Comma rune
+	// Comment, if not 0, marks lines starting with it as comments.
+	Comment rune
+	// RejectUnknownColumns fails on header columns that are not in the schema.
+	RejectUnknownColumns bool
+	// MaxInvalid stops processing once this many invalid rows have been
+	// seen. Zero means no limit.
+	MaxInvalid int
+}
+
+// CSVError describes a problem with a single cell of a CSV row.
+type CSVError struct {
+	Row     int // 1-based row number, the header being row 1
+	Column  string
+	Message string
+}
+
+// Error returns a string representation of the CSV error
+func (e CSVError) Error() string {
+	return fmt.Sprintf("row %d, column %q: %s", e.Row, e.Column, e.Message)
+}
+
+// CSVRowResult is the validation outcome of a single CSV row.
+type CSVRowResult struct {
+	Row     int
+	Offset  int64 // Byte offset where the row starts in the input
+	IsValid bool
+	Errors  []CSVError
+	// Warnings holds problems that do not make the row invalid, such as
+	// deprecated columns. See Rule.Severity.
+	Warnings []CSVError
+	// Record holds the row's cells coerced to their rule types. Empty cells
+	// are left out so that they count as missing fields, and cells that do
+	// not parse as their type are kept as strings.
+	Record map[string]interface{}
+}
+
+// ValidateCSV reads CSV data with a header row from r and validates every row
+// against schema, one row at a time. See Validator.ValidateCSV.
+func ValidateCSV(r io.Reader, schema Schema, opts CSVOptions, fn func(CSVRowResult) error) (StreamSummary, error) {
+	return defaultValidator.ValidateCSV(context.Background(), r, schema, opts, fn)
+}
+
+// ValidateCSV is like the package-level ValidateCSV, validating every row
+// with c.ValidateContext. It stops once ctx is done and returns ctx.Err().
+//
+// Header columns are mapped to schema fields by name and cell strings are
+// coerced to the rule's Type before validation: "int", "float" and "bool"
+// cells are parsed with strconv, "list" and "map" cells must hold JSON.
+// A cell that does not parse is validated as the string it holds, so it is
+// reported as a type mismatch like any other value of the wrong type.
+// A header that lacks a required column, repeats a column, or (with
+// RejectUnknownColumns) names a column the schema does not know is reported
+// as an error before any row is read. Required columns whose Required
+// constraint is only a warning may be missing; every row then gets the
+// warning instead.
+//
+// fn, when not nil, is called once per data row in input order. Returning an
+// error from fn stops processing and that error is returned.
+func (c *Validator) ValidateCSV(ctx context.Context, r io.Reader, schema Schema, opts CSVOptions, fn func(CSVRowResult) error) (StreamSummary, error) {
+	var summary StreamSummary
+
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.Comment = opts.Comment
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return summary, errors.New("missing CSV header")
+	}
+	if err != nil {
+		return summary, err
+	}
+	columns, err := checkCSVHeader(header, schema, c.registry, opts.RejectUnknownColumns)
+	if err != nil {
+		return summary, err
+	}
+
+	for row := 2; ; row++ {
+		offset := reader.InputOffset()
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return summary, nil
+		}
+		if err != nil {
+			return summary, err
+		}
+
+		res, err := c.validateCSVRow(ctx, row, cells, columns, schema)
+		if err != nil {
+			return summary, err
+		}
+		res.Offset = offset
+
+		summary.Records++
+		if res.IsValid {
+			summary.Valid++
+		} else {
+			summary.Invalid++
+		}
+		if fn != nil {
+			if err := fn(res); err != nil {
+				return summary, err
+			}
+		}
+		if opts.MaxInvalid > 0 && summary.Invalid >= opts.MaxInvalid {
+			summary.Stopped = true
+			return summary, nil
+		}
+	}
+}
+
+// checkCSVHeader validates the header row and returns a copy of the column
+// names.
+func checkCSVHeader(header []string, schema Schema, registry *Registry, rejectUnknown bool) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	var unknown []string
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %q in CSV header", name)
+		}
+		seen[name] = true
+		columns[i] = name
+		if _, ok := schema[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	var missing []string
+	for field, rule := range schema {
+		if resolved, err := registry.resolve(rule); err == nil {
+			rule = resolved
+		}
+		if rule.Required && !seen[field] && rule.severity(CodeRequired) == SeverityError {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+	if rejectUnknown && len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown columns: %s", strings.Join(unknown, ", "))
+	}
+	return columns, nil
+}
+
+func (c *Validator) validateCSVRow(ctx context.Context, row int, cells []string, columns []string, schema Schema) (CSVRowResult, error) {
+	if err := ctx.Err(); err != nil {
+		return CSVRowResult{}, err
+	}
+
+	res := CSVRowResult{Row: row, Record: make(map[string]interface{}, len(columns))}
+
+	if len(cells) > len(columns) {
+		res.Errors = append(res.Errors, CSVError{
+			Row:     row,
+			Column:  "",
+			Message: fmt.Sprintf("Row has %d fields, header has %d", len(cells), len(columns)),
+		})
+	}
+
+	for i, column := range columns {
+		if i >= len(cells) || cells[i] == "" {
+			continue
+		}
+		rule := schema[column]
+		if resolved, err := c.registry.resolve(rule); err == nil {
+			rule = resolved
+		}
+		value, err := coerceCell(cells[i], rule.Type)
+		if err != nil {
+			// Validation reports the string as a type mismatch
+			value = cells[i]
+		}
+		res.Record[column] = value
+	}
+
+	result, err := c.ValidateContext(ctx, res.Record, schema)
+	if err != nil {
+		return CSVRowResult{}, err
+	}
+	for _, err := range result.Errors {
+		res.Errors = append(res.Errors, CSVError{Row: row, Column: err.Field, Message: err.Message})
+	}
+	for _, err := range result.Warnings {
+		res.Warnings = append(res.Warnings, CSVError{Row: row, Column: err.Field, Message: err.Message})
+	}
+
+	res.IsValid = len(res.Errors) == 0
+	return res, nil
+}
+
+// coerceCell converts a CSV cell to the Go type used for typ.
+func coerceCell(cell string, typ string) (interface{}, error) {
+	switch typ {
+	case "int":
+		return strconv.ParseInt(strings.TrimSpace(cell), 10, 64)
+	case "float":
+		return strconv.ParseFloat(strings.TrimSpace(cell), 64)
+	case "bool":
+		return strconv.ParseBool(strings.TrimSpace(cell))
+	case "list":
+		var v []interface{}
+		err := json.Unmarshal([]byte(cell), &v)
+		return v, err
+	case "map":
+		var v map[string]interface{}
+		err := json.Unmarshal([]byte(cell), &v)
+		return v, err
+	default:
+		return cell, nil
+	}
+}
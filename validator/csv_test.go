@@ -0,0 +1,236 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var csvSchema = Schema{
+	"name":   {Type: "string", Required: true, MinLength: 2},
+	"age":    {Type: "int", Min: 18},
+	"score":  {Type: "float"},
+	"active": {Type: "bool"},
+	"tags":   {Type: "list", List: &Rule{Type: "string"}},
+}
+
+func collectCSV(t *testing.T, input string, opts CSVOptions) ([]CSVRowResult, StreamSummary) {
+	t.Helper()
+	var results []CSVRowResult
+	summary, err := ValidateCSV(strings.NewReader(input), csvSchema, opts, func(r CSVRowResult) error {
+		results = append(results, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ValidateCSV returned error: %v", err)
+	}
+	return results, summary
+}
+
+func TestValidateCSV(t *testing.T) {
+	input := "name,age,score,active,tags,notes\n" +
+		"Alice,30,9.5,true,\"[\"\"a\"\",\"\"b\"\"]\",hello\n" +
+		"Bob,abc,1,yes,,\n" +
+		",17,,,,\n"
+
+	results, summary := collectCSV(t, input, CSVOptions{})
+
+	if summary.Records != 3 || summary.Valid != 1 || summary.Invalid != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	alice := results[0]
+	if !alice.IsValid {
+		t.Errorf("Expected row 2 to be valid, got %v", alice.Errors)
+	}
+	if alice.Row != 2 || alice.Record["age"] != int64(30) || alice.Record["active"] != true {
+		t.Errorf("unexpected coerced record: %+v", alice)
+	}
+	if alice.Record["notes"] != "hello" {
+		t.Errorf("Expected unknown column to be kept as string, got %v", alice.Record["notes"])
+	}
+
+	bob := results[1]
+	if len(bob.Errors) != 2 {
+		t.Fatalf("Expected 2 errors for row 3, got %v", bob.Errors)
+	}
+	for _, err := range bob.Errors {
+		if err.Column != "age" && err.Column != "active" {
+			t.Errorf("unexpected error column: %v", err)
+		}
+	}
+
+	missing := results[2]
+	columns := map[string]bool{}
+	for _, err := range missing.Errors {
+		columns[err.Column] = true
+	}
+	if !columns["name"] || !columns["age"] {
+		t.Errorf("Expected required name and out-of-range age errors, got %v", missing.Errors)
+	}
+}
+
+func TestCSVErrorString(t *testing.T) {
+	err := CSVError{Row: 4, Column: "age", Message: "Field is required"}
+	want := `row 4, column "age": Field is required`
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestValidateCSVHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    CSVOptions
+		wantErr bool
+	}{
+		{name: "Valid header", input: "name,age\n", wantErr: false},
+		{name: "Missing required column", input: "age\n", wantErr: true},
+		{name: "Duplicate column", input: "name,name\n", wantErr: true},
+		{name: "Unknown column allowed", input: "name,extra\n", wantErr: false},
+		{name: "Unknown column rejected", input: "name,extra\n", opts: CSVOptions{RejectUnknownColumns: true}, wantErr: true},
+		{name: "Empty input", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateCSV(strings.NewReader(tt.input), csvSchema, tt.opts, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCSVOptions(t *testing.T) {
+	input := "# exported data\nname;age\nAl;20\nBo;20;extra\nX;20\nYy;20\n"
+
+	results, summary := collectCSV(t, input, CSVOptions{Comma: ';', Comment: '#', MaxInvalid: 2})
+
+	if !summary.Stopped || len(results) != 3 {
+		t.Fatalf("Expected to stop after the second invalid row, got %d rows (%+v)", len(results), summary)
+	}
+	if !results[0].IsValid {
+		t.Errorf("Expected first row to be valid, got %v", results[0].Errors)
+	}
+	if results[1].IsValid || results[1].Errors[0].Column != "" {
+		t.Errorf("Expected a field count error, got %v", results[1].Errors)
+	}
+	if results[0].Offset != int64(strings.Index(input, "Al;")) {
+		t.Errorf("unexpected offset %d", results[0].Offset)
+	}
+}
+
+func TestValidateCSVWarnings(t *testing.T) {
+	schema := Schema{
+		"name":  {Type: "string", Required: true},
+		"phone": {Type: "string", Required: true, Severity: SeverityWarning},
+		"fax":   {Type: "string", Deprecated: "use phone"},
+	}
+	input := "name,fax\nAnn,555\n"
+
+	var results []CSVRowResult
+	summary, err := ValidateCSV(strings.NewReader(input), schema, CSVOptions{}, func(r CSVRowResult) error {
+		results = append(results, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected a missing warning-only required column to be accepted, got %v", err)
+	}
+	if summary.Valid != 1 || len(results) != 1 || !results[0].IsValid || len(results[0].Errors) != 0 {
+		t.Fatalf("Expected a valid row, got %+v", results)
+	}
+	columns := map[string]bool{}
+	for _, w := range results[0].Warnings {
+		columns[w.Column] = true
+	}
+	if len(results[0].Warnings) != 2 || !columns["phone"] || !columns["fax"] {
+		t.Errorf("Expected phone and fax warnings, got %v", results[0].Warnings)
+	}
+}
+
+func TestValidateCSVCoercion(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		valid    bool
+		errors   []string
+		warnings []string
+	}{
+		{
+			name:   "Required column with a bad cell",
+			rule:   Rule{Type: "int", Required: true},
+			errors: []string{"Invalid type: expected int, got string"},
+		},
+		{
+			name:     "Type mismatch as a warning",
+			rule:     Rule{Type: "int", Required: true, Severity: SeverityWarning},
+			valid:    true,
+			warnings: []string{"Invalid type: expected int, got string"},
+		},
+		{
+			name:   "Custom message",
+			rule:   Rule{Type: "int", Messages: &Messages{TypeMismatch: strPtr("age must be a number")}},
+			errors: []string{"age must be a number"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res CSVRowResult
+			_, err := ValidateCSV(strings.NewReader("age\nabc\n"), Schema{"age": tt.rule}, CSVOptions{}, func(r CSVRowResult) error {
+				res = r
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.IsValid != tt.valid || !reflect.DeepEqual(csvMessages(res.Errors), tt.errors) ||
+				!reflect.DeepEqual(csvMessages(res.Warnings), tt.warnings) {
+				t.Errorf("got valid %v, errors %v, warnings %v", res.IsValid, res.Errors, res.Warnings)
+			}
+		})
+	}
+}
+
+func csvMessages(errs []CSVError) []string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
+
+func TestValidatorValidateCSV(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterRule("age", Rule{Type: "int", Required: true, Min: 18}); err != nil {
+		t.Fatal(err)
+	}
+	v := New(WithRegistry(registry), WithFailFast(true))
+	schema := Schema{"age": {Ref: "age"}, "name": {Type: "string", MinLength: 3}}
+
+	if _, err := v.ValidateCSV(context.Background(), strings.NewReader("name\nAnn\n"), schema, CSVOptions{}, nil); err == nil {
+		t.Errorf("Expected the required column of the definition to be checked")
+	}
+
+	var results []CSVRowResult
+	summary, err := v.ValidateCSV(context.Background(), strings.NewReader("age,name\n20,Ann\n17,Al\n"), schema, CSVOptions{},
+		func(r CSVRowResult) error {
+			results = append(results, r)
+			return nil
+		})
+	if err != nil || summary.Valid != 1 || summary.Invalid != 1 {
+		t.Fatalf("unexpected summary %+v, err %v", summary, err)
+	}
+	if len(results[1].Errors) != 1 {
+		t.Errorf("Expected fail-fast to stop at one error, got %v", results[1].Errors)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.ValidateCSV(ctx, strings.NewReader("age\n20\n"), schema, CSVOptions{}, nil); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
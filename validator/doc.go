@@ -0,0 +1,275 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// docRow is one line of the field table written by RenderMarkdown and
+// RenderHTML.
+type docRow struct {
+	path        string
+	typ         string
+	required    string
+	def         string
+	rule        Rule // For the constraints
+	title       string
+	description string
+	examples    []string
+	deprecated  string
+}
+
+// RenderMarkdown documents schema as a Markdown table with one row per field
+// path, type, required flag, default, constraints and description. Nested
+// maps are listed as "address.zip" and list items as "tags[]". Fields are
+// sorted, so the output is deterministic and can be checked in and diffed.
+// Rules with a Ref are listed as "ref name"; use Registry.RenderMarkdown to
+// expand them.
+func RenderMarkdown(schema Schema) string {
+	return renderMarkdown(schema, nil)
+}
+
+// RenderMarkdown is like the package-level RenderMarkdown, with every Ref
+// expanded to the definition it names, whose rows follow as for a nested
+// schema. Recursive definitions are expanded once.
+func (r *Registry) RenderMarkdown(schema Schema) string {
+	return renderMarkdown(schema, r)
+}
+
+func renderMarkdown(schema Schema, registry *Registry) string {
+	var b strings.Builder
+	b.WriteString("| Field | Type | Required | Default | Constraints | Description |\n")
+	b.WriteString("|-------|------|----------|---------|-------------|-------------|\n")
+	for _, row := range docRows(schema, "", registry, map[string]bool{}) {
+		var desc []string
+		if row.title != "" {
+			desc = append(desc, "**"+row.title+"**")
+		}
+		if row.description != "" {
+			desc = append(desc, row.description)
+		}
+		if row.deprecated != "" {
+			desc = append(desc, "*Deprecated:* "+row.deprecated)
+		}
+		if len(row.examples) > 0 {
+			desc = append(desc, "Examples: "+codeList(row.examples, markdownCode))
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			markdownCell(row.path), markdownCell(row.typ), row.required,
+			markdownCell(markdownCode(row.def)),
+			markdownCell(strings.Join(docConstraints(row.rule, identity, markdownCode), ", ")),
+			markdownCell(strings.Join(desc, " ")))
+	}
+	return b.String()
+}
+
+// RenderHTML documents schema like RenderMarkdown, as an HTML table.
+func RenderHTML(schema Schema) string {
+	return renderHTML(schema, nil)
+}
+
+// RenderHTML documents schema like Registry.RenderMarkdown, as an HTML
+// table.
+func (r *Registry) RenderHTML(schema Schema) string {
+	return renderHTML(schema, r)
+}
+
+func renderHTML(schema Schema, registry *Registry) string {
+	var b strings.Builder
+	b.WriteString("<table>\n<thead>\n<tr><th>Field</th><th>Type</th><th>Required</th><th>Default</th><th>Constraints</th><th>Description</th></tr>\n</thead>\n<tbody>\n")
+	for _, row := range docRows(schema, "", registry, map[string]bool{}) {
+		var desc []string
+		if row.title != "" {
+			desc = append(desc, "<strong>"+html.EscapeString(row.title)+"</strong>")
+		}
+		if row.description != "" {
+			desc = append(desc, html.EscapeString(row.description))
+		}
+		if row.deprecated != "" {
+			desc = append(desc, "<em>Deprecated:</em> "+html.EscapeString(row.deprecated))
+		}
+		if len(row.examples) > 0 {
+			desc = append(desc, "Examples: "+codeList(row.examples, htmlCode))
+		}
+		constraints := docConstraints(row.rule, html.EscapeString, htmlCode)
+		fmt.Fprintf(&b, "<tr><td><code>%s</code></td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(row.path), html.EscapeString(row.typ), row.required,
+			htmlCode(row.def),
+			strings.Join(constraints, ", "), strings.Join(desc, " "))
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String()
+}
+
+// docRows lists the rows for schema and everything nested in it, with
+// field paths starting with prefix. Refs are expanded with registry, if
+// any, except to the definitions in active, which are being expanded.
+func docRows(schema Schema, prefix string, registry *Registry, active map[string]bool) []docRow {
+	fields := make([]string, 0, len(schema))
+	for field := range schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var rows []docRow
+	for _, field := range fields {
+		rule := schema[field]
+		required := "no"
+		if resolved, err := registry.resolve(rule); rule.Required || (registry != nil && err == nil && resolved.Required) {
+			required = "yes"
+		}
+		rows = append(rows, ruleRows(rule, prefix+field, required, registry, active)...)
+	}
+	return rows
+}
+
+// ruleRows returns the row for rule at path followed by the rows of its list
+// items and nested fields.
+func ruleRows(rule Rule, path, required string, registry *Registry, active map[string]bool) []docRow {
+	typ := rule.Type
+	if name := rule.Ref; name != "" {
+		typ = "ref " + name
+		if resolved, err := registry.resolve(rule); registry != nil && err == nil && !active[name] {
+			active[name] = true
+			defer delete(active, name)
+			rule = resolved
+			typ = rule.Type + " (" + name + ")"
+		}
+	}
+
+	row := docRow{
+		path:        path,
+		typ:         typ,
+		required:    required,
+		rule:        rule,
+		title:       rule.Title,
+		description: rule.Description,
+		deprecated:  rule.Deprecated,
+	}
+	if rule.Default != nil {
+		row.def = docValue(rule.Default)
+	}
+	for _, example := range rule.Examples {
+		row.examples = append(row.examples, docValue(example))
+	}
+
+	rows := []docRow{row}
+	if rule.List != nil {
+		rows = append(rows, ruleRows(*rule.List, path+"[]", "", registry, active)...)
+	}
+	if rule.Schema != nil {
+		rows = append(rows, docRows(*rule.Schema, path+".", registry, active)...)
+	}
+	return rows
+}
+
+// docConstraints describes the constraints of rule in words escaped with
+// text, with patterns and values marked up with code.
+func docConstraints(rule Rule, text, code func(string) string) []string {
+	var c []string
+	add := func(constraint, words string) {
+		if rule.severity(constraint) == SeverityWarning {
+			words += " (warning)"
+		}
+		c = append(c, words)
+	}
+	if rule.Min != 0 {
+		add(CodeMin, fmt.Sprintf("min %v", rule.Min))
+	}
+	if rule.Max != 0 {
+		add(CodeMax, fmt.Sprintf("max %v", rule.Max))
+	}
+	if rule.MinLength != 0 {
+		add(CodeMinLength, fmt.Sprintf("min length %d", rule.MinLength))
+	}
+	if rule.MaxLength != 0 {
+		add(CodeMaxLength, fmt.Sprintf("max length %d", rule.MaxLength))
+	}
+	if pattern := rule.RegexPattern; pattern != "" || rule.Regex != nil {
+		if pattern == "" {
+			pattern = rule.Regex.String()
+		}
+		add(CodePattern, "pattern "+code(pattern))
+	}
+	if rule.Format != "" {
+		add(CodeFormat, "format "+text(rule.Format))
+	}
+	if rule.Allowed != nil {
+		values := make([]string, len(rule.Allowed))
+		for i, v := range rule.Allowed {
+			values[i] = docValue(v)
+		}
+		add(CodeAllowed, "one of "+codeList(values, code))
+	}
+	if rule.Check != "" {
+		add(CodeCheck, "check "+text(rule.Check))
+	}
+	if rule.Required && rule.severity(CodeRequired) == SeverityWarning {
+		c = append(c, "required (warning)")
+	}
+	return c
+}
+
+// docValue renders a default, allowed or example value as JSON.
+func docValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// codeList marks up every value with code and joins them with commas.
+func codeList(values []string, code func(string) string) string {
+	wrapped := make([]string, len(values))
+	for i, v := range values {
+		wrapped[i] = code(v)
+	}
+	return strings.Join(wrapped, ", ")
+}
+
+// markdownCode returns s as a Markdown code span, or "" if s is empty. The
+// span is delimited by more backticks than s holds in a row, so that it
+// cannot end early.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+// htmlCode returns s escaped in an HTML code element, or "" if s is empty.
+func htmlCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "<code>" + html.EscapeString(s) + "</code>"
+}
+
+// identity returns s unchanged.
+func identity(s string) string {
+	return s
+}
+
+// markdownCell escapes s for a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(s)
+}
@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var docSchema = Schema{
+	"name": {Type: "string", Required: true, MinLength: 2, MaxLength: 50, Title: "Name", Description: "Full name",
+		Examples: []interface{}{"Ada"}},
+	"role": {Type: "string", Default: "user", Allowed: []interface{}{"admin", "user"}},
+	"code": {Type: "string", RegexPattern: "^a|b$", Regex: regexp.MustCompile("^a|b$")},
+	"fax":  {Type: "string", Deprecated: "use phone"},
+	"key":  {Type: "string", RegexPattern: "^a*_b*_c$", Allowed: []interface{}{"a*_b*_c", "x`y"}},
+	"age":  {Type: "int", Min: 18, Max: 99, Warn: []string{CodeMax}},
+	"orders": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+		"price": {Type: "float", Required: true, Min: 0.5},
+	}}},
+	"address": {Ref: "address", Description: "<Home> & work"},
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	want := strings.Join([]string{
+		"| Field | Type | Required | Default | Constraints | Description |",
+		"|-------|------|----------|---------|-------------|-------------|",
+		"| `address` | ref address | no |  |  | <Home> & work |",
+		"| `age` | int | no |  | min 18, max 99 (warning) |  |",
+		"| `code` | string | no |  | pattern `^a\\|b$` |  |",
+		"| `fax` | string | no |  |  | *Deprecated:* use phone |",
+		"| `key` | string | no |  | pattern `^a*_b*_c$`, one of `\"a*_b*_c\"`, ``\"x`y\"`` |  |",
+		"| `name` | string | yes |  | min length 2, max length 50 | **Name** Full name Examples: `\"Ada\"` |",
+		"| `orders` | list | no |  |  |  |",
+		"| `orders[]` | map |  |  |  |  |",
+		"| `orders[].price` | float | yes |  | min 0.5 |  |",
+		"| `role` | string | no | `\"user\"` | one of `\"admin\"`, `\"user\"` |  |",
+	}, "\n") + "\n"
+
+	got := RenderMarkdown(docSchema)
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if again := RenderMarkdown(docSchema); again != got {
+		t.Errorf("Expected deterministic output")
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	got := RenderHTML(docSchema)
+	for _, want := range []string{
+		"<tr><th>Field</th><th>Type</th><th>Required</th><th>Default</th><th>Constraints</th><th>Description</th></tr>",
+		"<tr><td><code>address</code></td><td>ref address</td><td>no</td><td></td><td></td><td>&lt;Home&gt; &amp; work</td></tr>",
+		"<tr><td><code>orders[].price</code></td><td>float</td><td>yes</td><td></td><td>min 0.5</td><td></td></tr>",
+		"<tr><td><code>role</code></td><td>string</td><td>no</td><td><code>&#34;user&#34;</code></td><td>one of <code>&#34;admin&#34;</code>, <code>&#34;user&#34;</code></td><td></td></tr>",
+		"<td>pattern <code>^a*_b*_c$</code>, one of <code>&#34;a*_b*_c&#34;</code>, <code>&#34;x`y&#34;</code></td>",
+		"<td><strong>Name</strong> Full name Examples: <code>&#34;Ada&#34;</code></td>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML output missing %q:\n%s", want, got)
+		}
+	}
+	if !strings.HasPrefix(got, "<table>\n") || !strings.HasSuffix(got, "</table>\n") {
+		t.Errorf("Expected a complete table, got:\n%s", got)
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"a*b*", "`a*b*`"},
+		{"x`y", "``x`y``"},
+		{"`a``", "``` `a`` ```"},
+	}
+	for _, tt := range tests {
+		if got := markdownCode(tt.in); got != tt.want {
+			t.Errorf("markdownCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryRenderMarkdown(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterSchema("address", Schema{"zip": {Type: "string", Required: true, MinLength: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterRule("email", Rule{Type: "string", Required: true, Format: "email"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterSchema("comment", Schema{
+		"text":    {Type: "string", Required: true},
+		"replies": {Type: "list", List: &Rule{Ref: "comment"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	schema := Schema{
+		"home":    {Ref: "address", Description: "Where they live"},
+		"contact": {Ref: "email"},
+		"thread":  {Ref: "comment"},
+		"other":   {Ref: "missing"},
+	}
+
+	want := strings.Join([]string{
+		"| Field | Type | Required | Default | Constraints | Description |",
+		"|-------|------|----------|---------|-------------|-------------|",
+		"| `contact` | string (email) | yes |  | format email |  |",
+		"| `home` | map (address) | no |  |  | Where they live |",
+		"| `home.zip` | string | yes |  | min length 5 |  |",
+		"| `other` | ref missing | no |  |  |  |",
+		"| `thread` | map (comment) | no |  |  |  |",
+		"| `thread.replies` | list | no |  |  |  |",
+		"| `thread.replies[]` | ref comment |  |  |  |  |",
+		"| `thread.text` | string | yes |  |  |  |",
+	}, "\n") + "\n"
+	if got := registry.RenderMarkdown(schema); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := RenderMarkdown(schema); !strings.Contains(got, "| `home` | ref address | no |") || strings.Contains(got, "home.zip") {
+		t.Errorf("Expected refs to stay unexpanded without a registry:\n%s", got)
+	}
+	if got := registry.RenderHTML(schema); !strings.Contains(got, "<tr><td><code>home.zip</code></td><td>string</td><td>yes</td>") {
+		t.Errorf("Expected the referenced fields in HTML:\n%s", got)
+	}
+}
@@ -1,5 +1,10 @@
 package validator
 
+import (
+	"fmt"
+	"strings"
+)
+
 type ErrorMessages struct {
 	Required     *string `json:"required,omitempty"`
 	TypeMismatch *string `json:"type_mismatch,omitempty"`
@@ -10,3 +15,122 @@ type ErrorMessages struct {
 	Regex        *string `json:"regex,omitempty"`
 	CustomError  *string `json:"custom_error,omitempty"`
 }
+
+// Error codes identify the constraint behind a ValidationError, independent
+// of its message.
+const (
+	CodeRequired     = "required"      // A required field is missing
+	CodeTypeMismatch = "type_mismatch" // {type}, {value}
+	CodeMin          = "min"           // {min}, {value}
+	CodeMax          = "max"           // {max}, {value}
+	CodeMinLength    = "min_length"    // {min}, {length}
+	CodeMaxLength    = "max_length"    // {max}, {length}
+	CodePattern      = "pattern"       // {pattern}, {value}
+	CodeAllowed      = "allowed"       // {allowed}, {value}
+	CodeFormat       = "format"        // {format}, {value}
+	CodeCheck        = "check"         // {check}, {error}
+	CodeUnknownField = "unknown_field" // A field not in the schema
+	CodeMaxDepth     = "max_depth"     // {max}
+	CodeReference    = "reference"     // {error}
+	CodeDeprecated   = "deprecated"    // {reason}
+)
+
+// Locale maps error codes to message templates. A template may use the
+// placeholders listed next to each code, e.g.
+//
+//	Locale{CodeMin: "Debe ser al menos {min}"}
+//
+// Codes missing from a Locale keep their default English message, and a
+// custom message set on the rule always wins.
+type Locale map[string]string
+
+// violation is a failed constraint before it becomes a ValidationError.
+type violation struct {
+	code    string
+	message string                 // Default message
+	params  map[string]interface{} // Values for the template placeholders
+}
+
+// render returns the message for f: custom if set, otherwise the locale
+// template for its code, otherwise the default message.
+func (l Locale) render(f violation, custom *string) string {
+	if custom != nil {
+		return *custom
+	}
+	template, ok := l[f.code]
+	if !ok {
+		return f.message
+	}
+	pairs := make([]string, 0, 2*len(f.params))
+	for name, value := range f.params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// Severity tells whether a problem must be fixed or is only reported.
+type Severity int
+
+const (
+	SeverityError   Severity = iota // Rejects the schema or the data
+	SeverityWarning                 // Reported, but accepted
+)
+
+// String returns "error" or "warning".
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// MarshalText encodes the severity as its String.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes "error" or "warning".
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "error":
+		*s = SeverityError
+	case "warning":
+		*s = SeverityWarning
+	default:
+		return fmt.Errorf("invalid severity %q", text)
+	}
+	return nil
+}
+
+// SchemaProblem is one problem found in a schema.
+type SchemaProblem struct {
+	Path     string   `json:"path"` // e.g. "orders.items.schema.price"
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// String returns "path: message", with "warning: " before the message of
+// warnings.
+func (p SchemaProblem) String() string {
+	if p.Severity == SeverityWarning {
+		return fmt.Sprintf("%s: warning: %s", p.Path, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// SchemaError is the error returned by ValidateSchema. Problems lists
+// every problem of the schema, warnings included.
+type SchemaError struct {
+	Problems []SchemaProblem
+}
+
+// Error lists the problems with SeverityError.
+func (e *SchemaError) Error() string {
+	var msgs []string
+	for _, p := range e.Problems {
+		if p.Severity == SeverityError {
+			msgs = append(msgs, p.String())
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSeverityText(t *testing.T) {
+	problem := SchemaProblem{Path: "a", Severity: SeverityWarning, Message: "m"}
+	data, err := json.Marshal(problem)
+	if err != nil || string(data) != `{"path":"a","severity":"warning","message":"m"}` {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+
+	var decoded SchemaProblem
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != problem {
+		t.Errorf("Unmarshal() = %+v, %v", decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`{"severity":"fatal"}`), &decoded); err == nil {
+		t.Errorf("Expected an error for an unknown severity")
+	}
+	if SeverityError.String() != "error" {
+		t.Errorf("unexpected String() %q", SeverityError.String())
+	}
+}
+
+func TestSchemaErrorMessage(t *testing.T) {
+	err := &SchemaError{Problems: []SchemaProblem{
+		{Path: "a", Message: "first"},
+		{Path: "b", Severity: SeverityWarning, Message: "ignored"},
+		{Path: "c.items", Message: "second"},
+	}}
+	if got := err.Error(); got != "a: first; c.items: second" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -0,0 +1,168 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultExternalConcurrency is the number of external checks a validation
+// run executes at once unless WithExternalConcurrency says otherwise.
+const DefaultExternalConcurrency = 8
+
+// ExternalCheck is a check that needs I/O, such as a database lookup to
+// verify that a user_id exists. Rules name it in Check like a CheckFunc.
+//
+// External checks run after the synchronous traversal, concurrently, and
+// only for values that passed every other constraint of their rule. path is
+// the full path of the value, e.g. "orders[2].user_id". A non-nil error fails
+// the value with the error's text as message.
+type ExternalCheck interface {
+	Check(ctx context.Context, path string, value interface{}) error
+}
+
+// BatchCheck is an ExternalCheck that can check many values in one call,
+// e.g. with a single "WHERE id IN (...)" query. When a check implements it,
+// every value it applies to in a document is passed to one CheckBatch call.
+type BatchCheck interface {
+	ExternalCheck
+	// CheckBatch returns one error per value, nil for values that pass. If
+	// it returns a different number of errors, every value fails.
+	CheckBatch(ctx context.Context, paths []string, values []interface{}) []error
+}
+
+// WithExternalCheck registers an external check that rules can name in
+// Check. It replaces a CheckFunc of the same name.
+func WithExternalCheck(name string, check ExternalCheck) Option {
+	return func(v *Validator) {
+		delete(v.checks, name)
+		v.external[name] = check
+	}
+}
+
+// WithExternalConcurrency limits how many external checks, or batches, run
+// at once. It defaults to DefaultExternalConcurrency.
+func WithExternalConcurrency(n int) Option {
+	return func(v *Validator) { v.externalConcurrency = n }
+}
+
+// pendingCheck is an external check found during traversal, to be run once
+// the traversal is over.
+type pendingCheck struct {
+	path     Path
+	value    interface{}
+	check    string
+	custom   *string // Messages.Check of the rule
+	severity Severity
+}
+
+// prefixPending rewrites the paths of the checks queued since mark, which
+// are relative to a nested value, with rename.
+func (v *validation) prefixPending(mark int, rename func(path Path) Path) {
+	for i := mark; i < len(v.pending); i++ {
+		v.pending[i].path = rename(v.pending[i].path)
+	}
+}
+
+// listItemPath renames the path "items..." of a value checked inside a list
+// item to "field[i]...".
+func listItemPath(field string, i int) func(Path) Path {
+	return func(path Path) Path {
+		return append(Path{KeySegment(field), IndexSegment(i)}, path[1:]...)
+	}
+}
+
+// runExternal runs the queued external checks and returns their errors in
+// queue order, so the result does not depend on scheduling.
+func (v *validation) runExternal() []ValidationError {
+	if len(v.pending) == 0 || v.done() {
+		return nil
+	}
+
+	results := make([]error, len(v.pending))
+	var tasks []func()
+
+	byCheck := map[string][]int{}
+	var names []string
+	for i, p := range v.pending {
+		if _, ok := byCheck[p.check]; !ok {
+			names = append(names, p.check)
+		}
+		byCheck[p.check] = append(byCheck[p.check], i)
+	}
+	for _, name := range names {
+		indexes := byCheck[name]
+		check := v.external[name]
+		if batch, ok := check.(BatchCheck); ok {
+			tasks = append(tasks, func() {
+				paths := make([]string, len(indexes))
+				values := make([]interface{}, len(indexes))
+				for j, i := range indexes {
+					paths[j], values[j] = v.pending[i].path.String(), v.pending[i].value
+				}
+				errs := batch.CheckBatch(v.ctx, paths, values)
+				if len(errs) != len(indexes) {
+					// Results cannot be matched to values, so none pass
+					err := fmt.Errorf("batch check returned %d results for %d values", len(errs), len(indexes))
+					errs = make([]error, len(indexes))
+					for j := range errs {
+						errs[j] = err
+					}
+				}
+				for j, i := range indexes {
+					results[i] = errs[j]
+				}
+			})
+			continue
+		}
+		for _, i := range indexes {
+			i := i
+			tasks = append(tasks, func() {
+				results[i] = check.Check(v.ctx, v.pending[i].path.String(), v.pending[i].value)
+			})
+		}
+	}
+
+	concurrency := v.externalConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultExternalConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-v.ctx.Done():
+		}
+		if v.ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(task func()) {
+			defer func() { <-sem; wg.Done() }()
+			task()
+		}(task)
+	}
+	wg.Wait()
+	if v.done() {
+		return nil
+	}
+
+	var errs []ValidationError
+	for i, err := range results {
+		if err == nil {
+			continue
+		}
+		p := v.pending[i]
+		f := violation{CodeCheck, err.Error(), map[string]interface{}{"check": p.check, "error": err}}
+		var e ValidationError
+		if p.severity == SeverityWarning {
+			e = v.warn("", f, p.custom)
+		} else {
+			e = v.fail("", f, p.custom)
+		}
+		e.Path = p.path
+		errs = append(errs, e)
+	}
+	return errs
+}
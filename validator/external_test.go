@@ -0,0 +1,199 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeUsers is an in-memory ExternalCheck that tracks how it is called.
+type fakeUsers struct {
+	ids     map[interface{}]bool
+	delay   time.Duration
+	calls   int32
+	active  int32
+	maxSeen int32
+	mu      sync.Mutex
+	paths   []string
+}
+
+func (f *fakeUsers) Check(ctx context.Context, path string, value interface{}) error {
+	atomic.AddInt32(&f.calls, 1)
+	n := atomic.AddInt32(&f.active, 1)
+	defer atomic.AddInt32(&f.active, -1)
+	for {
+		seen := atomic.LoadInt32(&f.maxSeen)
+		if n <= seen || atomic.CompareAndSwapInt32(&f.maxSeen, seen, n) {
+			break
+		}
+	}
+	f.mu.Lock()
+	f.paths = append(f.paths, path)
+	f.mu.Unlock()
+
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if !f.ids[value] {
+		return fmt.Errorf("user %v does not exist", value)
+	}
+	return nil
+}
+
+// fakeBatchUsers answers every lookup of a document with one call.
+type fakeBatchUsers struct {
+	fakeUsers
+	batches [][]string
+	// broken makes CheckBatch return brokenCount errors, as a buggy query
+	// might.
+	broken      bool
+	brokenCount int
+}
+
+func (f *fakeBatchUsers) CheckBatch(ctx context.Context, paths []string, values []interface{}) []error {
+	f.batches = append(f.batches, paths)
+	if f.broken {
+		return make([]error, f.brokenCount)
+	}
+	errs := make([]error, len(values))
+	for i, value := range values {
+		if !f.ids[value] {
+			errs[i] = errors.New("unknown user")
+		}
+	}
+	return errs
+}
+
+var orderSchema = Schema{
+	"owner": {Type: "int", Required: true, Check: "user_exists"},
+	"lines": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+		"assignee": {Type: "int", Min: 1, Check: "user_exists"},
+	}}},
+	"meta": {Type: "map", Schema: &Schema{
+		"reviewer": {Type: "int", Check: "user_exists", Messages: &Messages{Check: strPtr("Unknown reviewer")}},
+	}},
+}
+
+func orderData(owner int, assignees ...interface{}) map[string]interface{} {
+	lines := make([]interface{}, len(assignees))
+	for i, a := range assignees {
+		lines[i] = map[string]interface{}{"assignee": a}
+	}
+	return map[string]interface{}{"owner": owner, "lines": lines, "meta": map[string]interface{}{"reviewer": 9}}
+}
+
+func TestExternalCheck(t *testing.T) {
+	users := &fakeUsers{ids: map[interface{}]bool{1: true, 2: true}}
+	v := New(WithExternalCheck("user_exists", users))
+
+	if err := v.ValidateSchema(orderSchema); err != nil {
+		t.Fatalf("Expected external checks to be known to ValidateSchema: %v", err)
+	}
+
+	result, err := v.ValidateContext(context.Background(), orderData(1, 2, 3, 0), orderSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, e := range result.Errors {
+		got = append(got, e.Field+": "+e.Message)
+	}
+	want := []string{
+		"lines[2].assignee: Value 0 is less than minimum 1",
+		"lines[1].assignee: user 3 does not exist",
+		"meta.reviewer: Unknown reviewer",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") || result.IsValid {
+		t.Errorf("got errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	sort.Strings(users.paths)
+	if paths := strings.Join(users.paths, ","); paths != "lines[0].assignee,lines[1].assignee,meta.reviewer,owner" {
+		t.Errorf("Expected values failing other rules to be skipped, got checks for %s", paths)
+	}
+}
+
+func TestExternalCheckWarning(t *testing.T) {
+	users := &fakeUsers{ids: map[interface{}]bool{1: true}}
+	schema := Schema{"owner": {Type: "int", Check: "user_exists", Warn: []string{CodeCheck}}}
+
+	result := New(WithExternalCheck("user_exists", users)).Validate(map[string]interface{}{"owner": 5}, schema)
+	if !result.IsValid || len(result.Warnings) != 1 || result.Warnings[0].Message != "user 5 does not exist" {
+		t.Errorf("Expected a warning, got errors %v and warnings %v", result.Errors, result.Warnings)
+	}
+
+	result = New(WithExternalCheck("user_exists", users), WithPromoteWarnings(true)).Validate(map[string]interface{}{"owner": 5}, schema)
+	if result.IsValid || len(result.Errors) != 1 || result.Errors[0].Code != CodeCheck {
+		t.Errorf("Expected a promoted error, got %v", result.Errors)
+	}
+}
+
+func TestExternalCheckConcurrency(t *testing.T) {
+	users := &fakeUsers{ids: map[interface{}]bool{1: true}, delay: 5 * time.Millisecond}
+	v := New(WithExternalCheck("user_exists", users), WithExternalConcurrency(3))
+
+	ids := make([]interface{}, 30)
+	for i := range ids {
+		ids[i] = 1
+	}
+	result := v.Validate(orderData(1, ids...), Schema{
+		"owner": orderSchema["owner"],
+		"lines": orderSchema["lines"],
+	})
+	if !result.IsValid {
+		t.Errorf("Expected valid data, got %v", result.Errors)
+	}
+	if users.maxSeen > 3 || users.maxSeen < 2 {
+		t.Errorf("Expected at most 3 concurrent checks, saw %d", users.maxSeen)
+	}
+}
+
+func TestExternalBatchCheck(t *testing.T) {
+	users := &fakeBatchUsers{fakeUsers: fakeUsers{ids: map[interface{}]bool{1: true, 2: true, 9: true}}}
+	v := New(WithExternalCheck("user_exists", users))
+
+	result := v.Validate(orderData(1, 2, 5), orderSchema)
+	if len(result.Errors) != 1 || result.Errors[0].Field != "lines[1].assignee" {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+	if len(users.batches) != 1 || len(users.batches[0]) != 4 || users.calls != 0 {
+		t.Errorf("Expected a single batch of 4 values, got %v and %d single calls", users.batches, users.calls)
+	}
+}
+
+func TestExternalBatchCheckResultCount(t *testing.T) {
+	for _, n := range []int{0, 2, 5} {
+		users := &fakeBatchUsers{fakeUsers: fakeUsers{ids: map[interface{}]bool{1: true, 2: true, 9: true}}, broken: true, brokenCount: n}
+		result := New(WithExternalCheck("user_exists", users)).Validate(orderData(1, 2), orderSchema)
+		if result.IsValid || len(result.Errors) != 3 {
+			t.Errorf("%d results: expected every value to fail, got %v", n, result.Errors)
+			continue
+		}
+		if want := fmt.Sprintf("batch check returned %d results for 3 values", n); result.Errors[0].Message != want {
+			t.Errorf("got message %q, want %q", result.Errors[0].Message, want)
+		}
+	}
+}
+
+func TestExternalCheckCancellation(t *testing.T) {
+	users := &fakeUsers{ids: map[interface{}]bool{1: true}, delay: time.Second}
+	v := New(WithExternalCheck("user_exists", users), WithExternalConcurrency(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := v.ValidateContext(ctx, orderData(1, 1, 1, 1), orderSchema)
+	if !errors.Is(err, context.DeadlineExceeded) || result.IsValid {
+		t.Errorf("Expected a deadline error, got %v, %+v", err, result)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected cancellation to stop pending checks, took %v", elapsed)
+	}
+}
@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FormatFunc reports whether a string is in a named format.
+type FormatFunc func(value string) bool
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// builtinFormats are the formats every Validator knows. Their names follow
+// the JSON Schema format vocabulary.
+var builtinFormats = map[string]FormatFunc{
+	"date": func(value string) bool {
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	},
+	"date-time": func(value string) bool {
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	},
+	"email": func(value string) bool {
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value
+	},
+	"uri": func(value string) bool {
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != ""
+	},
+	"uuid": uuidPattern.MatchString,
+	"ipv4": func(value string) bool {
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+	},
+	"ipv6": func(value string) bool {
+		return net.ParseIP(value) != nil && strings.Contains(value, ":")
+	},
+}
@@ -0,0 +1,46 @@
+package validator
+
+import "testing"
+
+func TestBuiltinFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		want   bool
+	}{
+		{"date", "2024-02-29", true},
+		{"date", "2023-02-29", false},
+		{"date-time", "2024-02-29T12:00:00Z", true},
+		{"date-time", "2024-02-29 12:00", false},
+		{"email", "ann@example.com", true},
+		{"email", "Ann <ann@example.com>", false},
+		{"uri", "https://example.com/a?b=c", true},
+		{"uri", "example.com", false},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"uuid", "123e4567e89b12d3a456426614174000", false},
+		{"ipv4", "192.168.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "2001:db8::1", true},
+		{"ipv6", "10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.value, func(t *testing.T) {
+			if got := builtinFormats[tt.format](tt.value); got != tt.want {
+				t.Errorf("%s(%q) = %v, want %v", tt.format, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRule(t *testing.T) {
+	schema := Schema{"email": {Type: "string", Format: "email", Messages: &Messages{Format: strPtr("Bad email")}}}
+
+	if result := Validate(map[string]interface{}{"email": "ann@example.com"}, schema); !result.IsValid {
+		t.Errorf("Expected valid email, got %v", result.Errors)
+	}
+	result := Validate(map[string]interface{}{"email": "ann"}, schema)
+	if len(result.Errors) != 1 || result.Errors[0].Message != "Bad email" || result.Errors[0].Code != CodeFormat {
+		t.Errorf("Expected a format error, got %v", result.Errors)
+	}
+}
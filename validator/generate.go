@@ -0,0 +1,388 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// generateAttempts is how many candidate values Generate tries for a field
+// before giving up on it.
+const generateAttempts = 100
+
+// generateDepth is the nesting depth below which Generate leaves out
+// optional fields and list items, so that recursive schemas end.
+const generateDepth = 8
+
+// Generate returns a random document that passes Validate against schema,
+// for tests and demos. The same src seed gives the same document. See
+// Validator.Generate.
+func Generate(schema Schema, src rand.Source) map[string]interface{} {
+	return defaultValidator.Generate(schema, src)
+}
+
+// GenerateDocument is like Generate but returns an error if it finds no
+// valid document. See Validator.GenerateDocument.
+func GenerateDocument(schema Schema, src rand.Source) (map[string]interface{}, error) {
+	return defaultValidator.GenerateDocument(schema, src)
+}
+
+// Generate is like GenerateDocument, but leaves out the required fields no
+// valid value is found for instead of returning an error, so the document
+// fails validation.
+func (c *Validator) Generate(schema Schema, src rand.Source) map[string]interface{} {
+	g := &generator{Validator: c, rnd: rand.New(src)}
+	data, _ := g.schema(schema, 0)
+	return data
+}
+
+// GenerateDocument returns a random document that passes c.Validate against
+// schema. It honors types, Required, Min and Max, lengths, Allowed, Default,
+// the built-in formats and Regex patterns written with literals, character
+// classes, ".", groups, alternation, repetition and anchors, and it
+// descends into nested maps and lists. Optional fields are included at
+// random, except deprecated ones.
+//
+// Each value is checked against its rule and generated again if it fails,
+// so constraints GenerateDocument does not understand, such as custom
+// checks and custom formats, are met as far as chance allows. Optional
+// fields no valid value is found for are left out, and so are optional
+// maps with such a required field. If a required field is left without a
+// valid value, e.g. a "uuid" with a MaxLength below 36, a custom type or a
+// check that always fails, GenerateDocument returns an error.
+func (c *Validator) GenerateDocument(schema Schema, src rand.Source) (map[string]interface{}, error) {
+	g := &generator{Validator: c, rnd: rand.New(src)}
+	data, err := g.schema(schema, 0)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// generator holds the state of one Generate call.
+type generator struct {
+	*Validator
+	rnd *rand.Rand
+	all bool // Include every optional field and at least one list item
+}
+
+// schema generates a map for schema. Required fields no valid value is
+// found for are left out of it and the first of them is reported as error.
+func (g *generator) schema(schema Schema, depth int) (map[string]interface{}, error) {
+	fields := make([]string, 0, len(schema))
+	for field := range schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	data := map[string]interface{}{}
+	var firstErr error
+	for _, field := range fields {
+		rule, err := g.registry.resolve(schema[field])
+		if err != nil {
+			continue
+		}
+		if !rule.Required && (rule.Deprecated != "" || depth >= generateDepth || (!g.all && g.rnd.Intn(2) == 0)) {
+			continue
+		}
+		value, ok := g.field(field, rule, data, depth)
+		if ok {
+			data[field] = value
+		} else if rule.Required && firstErr == nil {
+			firstErr = fmt.Errorf("cannot generate a valid value for required field '%s'", field)
+		}
+	}
+	return data, firstErr
+}
+
+// field generates values for rule until one passes it, or reports false if
+// none does within generateAttempts.
+func (g *generator) field(field string, rule Rule, data map[string]interface{}, depth int) (interface{}, bool) {
+	for attempt := 0; attempt < generateAttempts; attempt++ {
+		value := g.value(rule, depth)
+		if value == nil {
+			return nil, false
+		}
+		v := g.newValidation(context.Background())
+		if !hasErrors(v.validateField(field, value, rule, data)) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// value generates one candidate for rule, or nil for custom types and rules
+// no value can be generated for.
+func (g *generator) value(rule Rule, depth int) interface{} {
+	if rule.Default != nil && g.rnd.Intn(2) == 0 {
+		return rule.Default
+	}
+	if len(rule.Allowed) > 0 {
+		return rule.Allowed[g.rnd.Intn(len(rule.Allowed))]
+	}
+
+	switch rule.Type {
+	case "bool":
+		return g.rnd.Intn(2) == 1
+	case "int":
+		lo, hi := numericRange(rule)
+		low, high := int64(lo), int64(hi)
+		if high < low {
+			return int(low)
+		}
+		return int(low + g.rnd.Int63n(high-low+1))
+	case "float":
+		lo, hi := numericRange(rule)
+		return lo + g.rnd.Float64()*(hi-lo)
+	case "string":
+		return g.string(rule)
+	case "list":
+		list := []interface{}{}
+		if rule.List == nil || depth >= generateDepth {
+			return list
+		}
+		n := g.rnd.Intn(4)
+		if g.all && n == 0 {
+			n = 1
+		}
+		for i := n; i > 0; i-- {
+			if item, ok := g.field("items", *rule.List, nil, depth+1); ok {
+				list = append(list, item)
+			}
+		}
+		return list
+	case "map":
+		if rule.Schema == nil {
+			return map[string]interface{}{}
+		}
+		if data, err := g.schema(*rule.Schema, depth+1); err == nil {
+			return data
+		}
+	}
+	return nil
+}
+
+// numericRange returns the range numbers for rule are drawn from. An unset
+// bound lies 100 away from the other one.
+func numericRange(rule Rule) (float64, float64) {
+	switch {
+	case rule.Min != 0 && rule.Max != 0:
+		return rule.Min, rule.Max
+	case rule.Min != 0:
+		return rule.Min, rule.Min + 100
+	case rule.Max != 0:
+		return rule.Max - 100, rule.Max
+	}
+	return 0, 100
+}
+
+// string generates a string for rule, or nil if it has a built-in format
+// no value of its lengths can be in.
+func (g *generator) string(rule Rule) interface{} {
+	if fg, ok := formatGenerators[rule.Format]; ok && g.formats[rule.Format] == nil {
+		if s, ok := g.formatted(fg, rule); ok {
+			return s
+		}
+		return nil
+	}
+
+	maxRepeat := rule.MaxLength
+	if maxRepeat == 0 {
+		maxRepeat = rule.MinLength + 8
+	}
+	if rule.Regex != nil {
+		if re, err := syntax.Parse(rule.Regex.String(), syntax.Perl); err == nil {
+			var b strings.Builder
+			g.regex(&b, re, maxRepeat)
+			return b.String()
+		}
+	}
+
+	n := rule.MinLength
+	if maxRepeat > n {
+		n += g.rnd.Intn(maxRepeat - n + 1)
+	}
+	return g.word(n)
+}
+
+// word returns n random lowercase letters.
+func (g *generator) word(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + g.rnd.Intn(26))
+	}
+	return string(b)
+}
+
+// regex writes a random string matching re to b. Repetitions without an
+// upper bound repeat at most maxRepeat times.
+func (g *generator) regex(b *strings.Builder, re *syntax.Regexp, maxRepeat int) {
+	repeat := func(min, max int) {
+		if max < 0 || max > min+maxRepeat {
+			max = min + maxRepeat
+		}
+		for n := min + g.rnd.Intn(max-min+1); n > 0; n-- {
+			g.regex(b, re.Sub[0], maxRepeat)
+		}
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && g.rnd.Intn(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(g.classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(byte('a' + g.rnd.Intn(26)))
+	case syntax.OpCapture:
+		g.regex(b, re.Sub[0], maxRepeat)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regex(b, sub, maxRepeat)
+		}
+	case syntax.OpAlternate:
+		g.regex(b, re.Sub[g.rnd.Intn(len(re.Sub))], maxRepeat)
+	case syntax.OpStar:
+		repeat(0, -1)
+	case syntax.OpPlus:
+		repeat(1, -1)
+	case syntax.OpQuest:
+		repeat(0, 1)
+	case syntax.OpRepeat:
+		repeat(re.Min, re.Max)
+	}
+	// Anchors, word boundaries and empty matches write nothing
+}
+
+// classRune picks a rune from a character class given as ranges, preferring
+// printable ASCII so that lengths in bytes stay predictable.
+func (g *generator) classRune(ranges []rune) rune {
+	var ascii []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r <= '~'; r++ {
+			if r >= ' ' {
+				ascii = append(ascii, r)
+			}
+		}
+	}
+	if len(ascii) > 0 {
+		return ascii[g.rnd.Intn(len(ascii))]
+	}
+	if len(ranges) < 2 {
+		return 'a'
+	}
+	i := 2 * g.rnd.Intn(len(ranges)/2)
+	return ranges[i] + rune(g.rnd.Int63n(int64(ranges[i+1]-ranges[i])+1))
+}
+
+// formatGenerator produces values of one built-in format with a length, in
+// bytes, between min and max, where a max of 0 means no limit. Values are
+// typical long unless a rule asks for shorter ones.
+type formatGenerator struct {
+	min, max, typical int
+	generate          func(g *generator, n int) string // Value of length n
+}
+
+// formatGenerators produce values in the built-in formats.
+var formatGenerators = map[string]formatGenerator{
+	"date": {10, 10, 0, func(g *generator, _ int) string {
+		return generateTime(g).Format("2006-01-02")
+	}},
+	"date-time": {20, 20, 0, func(g *generator, _ int) string {
+		return generateTime(g).Format(time.RFC3339)
+	}},
+	"email": {3, 254, 11, func(g *generator, n int) string {
+		local := 1 + g.rnd.Intn((n-1)/2)
+		domain := n - 1 - local
+		if domain < 5 {
+			return g.word(local) + "@" + g.word(domain)
+		}
+		return g.word(local) + "@" + g.word(domain-4) + ".com"
+	}},
+	"uri": {4, 0, 21, func(g *generator, n int) string {
+		if n < 21 {
+			return "urn:" + g.word(n-4)
+		}
+		return "https://example.com/" + g.word(n-20)
+	}},
+	"uuid": {36, 36, 0, func(g *generator, _ int) string {
+		b := make([]byte, 16)
+		g.rnd.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	}},
+	"ipv4": {7, 15, 0, func(g *generator, n int) string {
+		octets := make([]string, 4)
+		for i, digits := range g.split(n-3, 4, 1, 3) {
+			lo, hi := [4]int{0, 0, 10, 100}[digits], [4]int{0, 9, 99, 255}[digits]
+			octets[i] = strconv.Itoa(lo + g.rnd.Intn(hi-lo+1))
+		}
+		return strings.Join(octets, ".")
+	}},
+	"ipv6": {15, 39, 0, func(g *generator, n int) string {
+		groups := make([]string, 8)
+		for i, digits := range g.split(n-7, 8, 1, 4) {
+			b := make([]byte, digits)
+			for j := range b {
+				b[j] = "0123456789abcdef"[g.rnd.Intn(16)]
+			}
+			groups[i] = string(b)
+		}
+		return strings.Join(groups, ":")
+	}},
+}
+
+// formatted generates a value for rule with fg, or reports false if no value
+// in the format fits the lengths of rule.
+func (g *generator) formatted(fg formatGenerator, rule Rule) (string, bool) {
+	lo, hi := fg.min, fg.max
+	if rule.MinLength > lo {
+		lo = rule.MinLength
+	}
+	if rule.MaxLength > 0 && (hi == 0 || rule.MaxLength < hi) {
+		hi = rule.MaxLength
+	}
+	if hi == 0 {
+		hi = lo + 16
+	}
+	if rule.MinLength == 0 && fg.typical > lo && fg.typical <= hi {
+		lo = fg.typical
+	}
+	if hi < lo {
+		return "", false
+	}
+	return fg.generate(g, lo+g.rnd.Intn(hi-lo+1)), true
+}
+
+// split returns parts random numbers between lo and hi adding up to total,
+// which must be within reach.
+func (g *generator) split(total, parts, lo, hi int) []int {
+	n := make([]int, parts)
+	for i := range n {
+		n[i] = lo
+	}
+	for rest := total - parts*lo; rest > 0; {
+		if i := g.rnd.Intn(parts); n[i] < hi {
+			n[i]++
+			rest--
+		}
+	}
+	return n
+}
+
+// generateTime returns a time between 2000 and 2030, in UTC.
+func generateTime(g *generator) time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(g.rnd.Int63n(30*365*24)) * time.Hour).Add(time.Duration(g.rnd.Intn(3600)) * time.Second)
+}
@@ -0,0 +1,183 @@
+package validator
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	schema := Schema{
+		"id":      {Type: "int", Required: true, Min: 10, Max: 20},
+		"score":   {Type: "float", Required: true, Min: -1.5, Max: 1.5},
+		"name":    {Type: "string", Required: true, MinLength: 3, MaxLength: 6},
+		"role":    {Type: "string", Required: true, Allowed: []interface{}{"admin", "user"}},
+		"plan":    {Type: "string", Required: true, Default: "free", Allowed: []interface{}{"free", "pro"}},
+		"active":  {Type: "bool", Required: true},
+		"code":    {Type: "string", Required: true, Regex: regexp.MustCompile(`^[A-Z]{3}-\d{4}$`)},
+		"email":   {Type: "string", Required: true, Format: "email"},
+		"born":    {Type: "string", Required: true, Format: "date"},
+		"id_v4":   {Type: "string", Required: true, Format: "uuid"},
+		"ip":      {Type: "string", Required: true, Format: "ipv6"},
+		"fax":     {Type: "string", Deprecated: "use phone"},
+		"comment": {Type: "string", MaxLength: 5},
+		"tags":    {Type: "list", Required: true, List: &Rule{Type: "string", Regex: regexp.MustCompile(`^(foo|bar)+baz?$`)}},
+		"address": {Type: "map", Required: true, Schema: &Schema{
+			"zip":    {Type: "string", Required: true, Regex: regexp.MustCompile(`^\d{5}$`)},
+			"street": {Type: "string", MinLength: 2},
+		}},
+		"orders": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+			"qty": {Type: "int", Required: true, Max: -5},
+		}}},
+	}
+
+	for seed := int64(0); seed < 50; seed++ {
+		data := Generate(schema, rand.NewSource(seed))
+		if result := Validate(data, schema); !result.IsValid || len(result.Warnings) != 0 {
+			t.Fatalf("seed %d: generated %v fails validation: %v %v", seed, data, result.Errors, result.Warnings)
+		}
+		if again := Generate(schema, rand.NewSource(seed)); !reflect.DeepEqual(again, data) {
+			t.Fatalf("seed %d: expected the same document, got\n%v\n%v", seed, data, again)
+		}
+	}
+
+	if a, b := Generate(schema, rand.NewSource(1)), Generate(schema, rand.NewSource(2)); reflect.DeepEqual(a, b) {
+		t.Errorf("Expected different seeds to give different documents")
+	}
+}
+
+func TestGenerateRegex(t *testing.T) {
+	patterns := []string{
+		`^[a-z]+$`,
+		`^(?i)abc$`,
+		`^\w+@\w+\.(com|org)$`,
+		`^[^0-9]{2,5}$`,
+		`^.{3}$`,
+		`^a*b?c+$`,
+		`^(ab){2,}$`,
+		`^\p{Greek}+$`,
+		`\bcat\b`,
+		`^[[:upper:]][[:digit:]]$`,
+	}
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			rule := Rule{Type: "string", Required: true, MinLength: 3, MaxLength: 30, Regex: regexp.MustCompile(pattern)}
+			if pattern == `^.{3}$` || pattern == `^[[:upper:]][[:digit:]]$` {
+				rule.MinLength, rule.MaxLength = 0, 0
+			}
+			schema := Schema{"value": rule}
+			for seed := int64(0); seed < 20; seed++ {
+				data := Generate(schema, rand.NewSource(seed))
+				if result := Validate(data, schema); !result.IsValid {
+					t.Fatalf("seed %d: %q fails: %v", seed, data["value"], result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorGenerate(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterSchema("node", Schema{
+		"name":     {Type: "string", Required: true, MinLength: 1},
+		"children": {Type: "list", List: &Rule{Ref: "node"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	v := New(WithRegistry(registry))
+	schema := Schema{"root": {Ref: "node", Required: true}}
+	for seed := int64(0); seed < 20; seed++ {
+		data := v.Generate(schema, rand.NewSource(seed))
+		if result := v.Validate(data, schema); !result.IsValid {
+			t.Fatalf("seed %d: generated %v fails validation: %v", seed, data, result.Errors)
+		}
+	}
+}
+
+func TestGenerateFormatLengths(t *testing.T) {
+	tests := []struct {
+		format         string
+		minLen, maxLen int
+	}{
+		{"email", 0, 6},
+		{"email", 3, 3},
+		{"email", 30, 40},
+		{"uri", 0, 5},
+		{"uri", 25, 0},
+		{"ipv4", 0, 8},
+		{"ipv4", 14, 0},
+		{"ipv6", 0, 20},
+		{"ipv6", 35, 39},
+		{"uuid", 36, 36},
+		{"date-time", 0, 20},
+	}
+	for _, tt := range tests {
+		schema := Schema{"value": {Type: "string", Required: true, Format: tt.format, MinLength: tt.minLen, MaxLength: tt.maxLen}}
+		for seed := int64(0); seed < 200; seed++ {
+			data := Generate(schema, rand.NewSource(seed))
+			if result := Validate(data, schema); !result.IsValid {
+				t.Fatalf("%s %d..%d, seed %d: %q fails: %v", tt.format, tt.minLen, tt.maxLen, seed, data["value"], result.Errors)
+			}
+		}
+	}
+}
+
+func TestGenerateUnsatisfiable(t *testing.T) {
+	rule := Rule{Type: "string", Format: "uuid", MaxLength: 10}
+	required := rule
+	required.Required = true
+	reject := New(WithCheck("never", func(CheckRequest) error { return errors.New("rejected") }))
+
+	tests := []struct {
+		name    string
+		v       *Validator
+		schema  Schema
+		wantErr string
+		omitted string
+	}{
+		{"optional field", defaultValidator, Schema{"id": rule, "name": {Type: "string", Required: true}}, "", "id"},
+		{"optional parent", defaultValidator, Schema{
+			"name":  {Type: "string", Required: true},
+			"owner": {Type: "map", Schema: &Schema{"id": required}},
+		}, "", "owner"},
+		{"required field", defaultValidator, Schema{"id": required}, "cannot generate a valid value for required field 'id'", ""},
+		{"required parent", defaultValidator, Schema{
+			"owner": {Type: "map", Required: true, Schema: &Schema{"id": required}},
+		}, "cannot generate a valid value for required field 'owner'", ""},
+		{"check always fails", reject, Schema{"name": {Type: "string", Required: true, Check: "never"}},
+			"cannot generate a valid value for required field 'name'", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 5; seed++ {
+				data, err := tt.v.GenerateDocument(tt.schema, rand.NewSource(seed))
+				if tt.wantErr != "" {
+					if err == nil || err.Error() != tt.wantErr {
+						t.Fatalf("seed %d: expected error %q, got %v", seed, tt.wantErr, err)
+					}
+					if data != nil {
+						t.Errorf("seed %d: expected no document, got %v", seed, data)
+					}
+					if cases := tt.v.InvalidCases(tt.schema, rand.NewSource(seed)); len(cases) != 0 {
+						t.Errorf("seed %d: expected no invalid cases, got %d", seed, len(cases))
+					}
+					if data := tt.v.Generate(tt.schema, rand.NewSource(seed)); tt.v.Validate(data, tt.schema).IsValid {
+						t.Errorf("seed %d: expected Generate to leave out the required field, got %v", seed, data)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("seed %d: unexpected error: %v", seed, err)
+				}
+				if _, ok := data[tt.omitted]; ok {
+					t.Errorf("seed %d: expected %s to be left out, got %v", seed, tt.omitted, data)
+				}
+				if result := tt.v.Validate(data, tt.schema); !result.IsValid {
+					t.Errorf("seed %d: generated %v fails validation: %v", seed, data, result.Errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package validator
+
+import "math"
+
+// InferOption configures Infer.
+type InferOption func(*inference)
+
+// InferBounds controls how sure Infer must be before it emits Min, Max,
+// MinLength and MaxLength: a field gets bounds only once it was observed at
+// least minObservations times. Zero turns bounds off. The default is 1.
+func InferBounds(minObservations int) InferOption {
+	return func(in *inference) { in.minObservations = minObservations }
+}
+
+// InferMargin widens the observed ranges and lengths by fraction of their
+// size on each side, e.g. 0.1 turns an observed 10..20 into 9..21, so that
+// data slightly outside the samples is still accepted. The default is 0.
+func InferMargin(fraction float64) InferOption {
+	return func(in *inference) { in.margin = fraction }
+}
+
+// inference holds the options of one Infer call.
+type inference struct {
+	minObservations int
+	margin          float64
+}
+
+// observation accumulates what the samples show about one field.
+type observation struct {
+	present int            // Number of samples holding the field, even null
+	nulls   int            // Number of null values
+	values  int            // Number of non-null values
+	types   map[string]int // Number of values matching each built-in type
+
+	min, max       float64 // Range of the numeric values
+	minLen, maxLen int     // Range of the string lengths, in bytes
+	items          *observation
+	maps           int // Number of map values, the denominator for Required
+	fields         map[string]*observation
+}
+
+// inferTypes lists the types Infer considers, in order of preference when a
+// value matches more than one, e.g. 3.0 is both an int and a float.
+var inferTypes = []string{"bool", "int", "float", "string", "list", "map"}
+
+// Infer derives a schema from sample documents, for data that has none yet.
+// Each field gets the Type every sample agrees on, Required if it is
+// present in every sample, nested Schema and List rules and, subject to
+// InferBounds and InferMargin, the observed numeric ranges and string
+// lengths. Fields that are ever null or whose values have no common type
+// are left out, so any value is accepted for them, and lists holding null
+// or mixed items get no List rule. The schema accepts every sample; it is a
+// starting point to be saved with MarshalSchema and edited by hand.
+func Infer(samples []map[string]interface{}, opts ...InferOption) Schema {
+	in := &inference{minObservations: 1}
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	root := &observation{}
+	for _, sample := range samples {
+		root.observe(sample)
+	}
+	return in.schema(root)
+}
+
+// observe records one value of the field.
+func (o *observation) observe(value interface{}) {
+	o.present++
+	if value == nil {
+		o.nulls++
+		return
+	}
+	if o.types == nil {
+		o.types = map[string]int{}
+	}
+	o.values++
+	for _, typ := range inferTypes {
+		if matchesType(value, typ) {
+			o.types[typ]++
+		}
+	}
+
+	first := o.values == 1
+	if f, ok := extractFloatValue(value); ok {
+		if first || f < o.min {
+			o.min = f
+		}
+		if first || f > o.max {
+			o.max = f
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		n := len(v)
+		if o.types["string"] == 1 || n < o.minLen {
+			o.minLen = n
+		}
+		if n > o.maxLen {
+			o.maxLen = n
+		}
+	case []interface{}:
+		if o.items == nil {
+			o.items = &observation{}
+		}
+		for _, item := range v {
+			o.items.observe(item)
+		}
+	case map[string]interface{}:
+		o.maps++
+		if o.fields == nil {
+			o.fields = map[string]*observation{}
+		}
+		for key, item := range v {
+			if o.fields[key] == nil {
+				o.fields[key] = &observation{}
+			}
+			o.fields[key].observe(item)
+		}
+	}
+}
+
+// typ returns the first type matched by every value, or "" if there is none
+// or a value was null, which no type matches.
+func (o *observation) typ() string {
+	if o.nulls > 0 {
+		return ""
+	}
+	for _, typ := range inferTypes {
+		if o.values > 0 && o.types[typ] == o.values {
+			return typ
+		}
+	}
+	return ""
+}
+
+// schema returns the schema for the fields of the maps seen by o.
+func (in *inference) schema(o *observation) Schema {
+	schema := Schema{}
+	for key, field := range o.fields {
+		rule, ok := in.rule(field)
+		if !ok {
+			continue
+		}
+		rule.Required = field.present == o.maps
+		schema[key] = rule
+	}
+	return schema
+}
+
+// rule returns the rule for the values seen by o, or false if they have no
+// common type.
+func (in *inference) rule(o *observation) (Rule, bool) {
+	rule := Rule{Type: o.typ()}
+	bounds := in.minObservations > 0 && o.values >= in.minObservations
+
+	switch rule.Type {
+	case "":
+		return Rule{}, false
+	case "int", "float":
+		if bounds {
+			margin := (o.max - o.min) * in.margin
+			rule.Min, rule.Max = o.min-margin, o.max+margin
+			if rule.Type == "int" {
+				rule.Min, rule.Max = math.Floor(rule.Min), math.Ceil(rule.Max)
+			}
+		}
+	case "string":
+		if bounds {
+			margin := float64(o.maxLen-o.minLen) * in.margin
+			rule.MinLength = int(math.Max(0, math.Floor(float64(o.minLen)-margin)))
+			rule.MaxLength = int(math.Ceil(float64(o.maxLen) + margin))
+		}
+	case "list":
+		if o.items != nil {
+			if items, ok := in.rule(o.items); ok {
+				rule.List = &items
+			}
+		}
+	case "map":
+		schema := in.schema(o)
+		rule.Schema = &schema
+	}
+	return rule, true
+}
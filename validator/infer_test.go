@@ -0,0 +1,255 @@
+package validator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func inferSamples(t *testing.T, docs ...string) []map[string]interface{} {
+	t.Helper()
+	samples := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		if err := json.Unmarshal([]byte(doc), &samples[i]); err != nil {
+			t.Fatalf("bad sample %d: %v", i, err)
+		}
+	}
+	return samples
+}
+
+func TestInfer(t *testing.T) {
+	samples := inferSamples(t,
+		`{"id": 1, "name": "Ann", "score": 2.5, "tags": ["a"], "address": {"zip": "12345", "city": "Lima"}, "note": null, "mixed": 1}`,
+		`{"id": 7, "name": "Bartholomew", "score": 4, "tags": [], "address": {"zip": "54321"}, "mixed": "x", "active": true}`,
+		`{"id": 3, "name": "Cy", "score": 3, "tags": ["bb", "ccc"], "address": {"zip": "00000"}, "orders": [{"qty": 2}, {"qty": 5, "sku": "X"}]}`,
+	)
+
+	tests := []struct {
+		name    string
+		opts    []InferOption
+		want    string
+		dataErr bool
+	}{
+		{
+			name: "observed bounds",
+			want: `{
+  "active": {
+    "type": "bool"
+  },
+  "address": {
+    "type": "map",
+    "required": true,
+    "schema": {
+      "city": {
+        "type": "string",
+        "min_length": 4,
+        "max_length": 4
+      },
+      "zip": {
+        "type": "string",
+        "required": true,
+        "min_length": 5,
+        "max_length": 5
+      }
+    }
+  },
+  "id": {
+    "type": "int",
+    "required": true,
+    "min": 1,
+    "max": 7
+  },
+  "name": {
+    "type": "string",
+    "required": true,
+    "min_length": 2,
+    "max_length": 11
+  },
+  "orders": {
+    "type": "list",
+    "list": {
+      "type": "map",
+      "schema": {
+        "qty": {
+          "type": "int",
+          "required": true,
+          "min": 2,
+          "max": 5
+        },
+        "sku": {
+          "type": "string",
+          "min_length": 1,
+          "max_length": 1
+        }
+      }
+    }
+  },
+  "score": {
+    "type": "float",
+    "required": true,
+    "min": 2.5,
+    "max": 4
+  },
+  "tags": {
+    "type": "list",
+    "required": true,
+    "list": {
+      "type": "string",
+      "min_length": 1,
+      "max_length": 3
+    }
+  }
+}
+`,
+		},
+		{
+			name: "bounds need enough observations",
+			opts: []InferOption{InferBounds(3)},
+			want: `{
+  "active": {
+    "type": "bool"
+  },
+  "address": {
+    "type": "map",
+    "required": true,
+    "schema": {
+      "city": {
+        "type": "string"
+      },
+      "zip": {
+        "type": "string",
+        "required": true,
+        "min_length": 5,
+        "max_length": 5
+      }
+    }
+  },
+  "id": {
+    "type": "int",
+    "required": true,
+    "min": 1,
+    "max": 7
+  },
+  "name": {
+    "type": "string",
+    "required": true,
+    "min_length": 2,
+    "max_length": 11
+  },
+  "orders": {
+    "type": "list",
+    "list": {
+      "type": "map",
+      "schema": {
+        "qty": {
+          "type": "int",
+          "required": true
+        },
+        "sku": {
+          "type": "string"
+        }
+      }
+    }
+  },
+  "score": {
+    "type": "float",
+    "required": true,
+    "min": 2.5,
+    "max": 4
+  },
+  "tags": {
+    "type": "list",
+    "required": true,
+    "list": {
+      "type": "string",
+      "min_length": 1,
+      "max_length": 3
+    }
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := Infer(samples, tt.opts...)
+			got, err := MarshalSchema(schema)
+			if err != nil {
+				t.Fatalf("MarshalSchema returned error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if err := ValidateSchema(schema); err != nil {
+				t.Errorf("Expected a valid schema, got %v", err)
+			}
+			for i, sample := range samples {
+				if result := Validate(sample, schema); !result.IsValid {
+					t.Errorf("sample %d does not pass the inferred schema: %v", i, result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestInferMargin(t *testing.T) {
+	samples := inferSamples(t, `{"n": 10, "s": "aaaaaaaaaa"}`, `{"n": 20, "s": "aaaaaaaaaaaaaaaaaaaa"}`)
+
+	tests := []struct {
+		name string
+		opts []InferOption
+		want Rule
+		str  Rule
+	}{
+		{"margin", []InferOption{InferMargin(0.15)}, Rule{Type: "int", Required: true, Min: 8, Max: 22},
+			Rule{Type: "string", Required: true, MinLength: 8, MaxLength: 22}},
+		{"no bounds", []InferOption{InferBounds(0)}, Rule{Type: "int", Required: true}, Rule{Type: "string", Required: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := Infer(samples, tt.opts...)
+			if got := schema["n"]; got.Type != tt.want.Type || got.Required != tt.want.Required || got.Min != tt.want.Min || got.Max != tt.want.Max {
+				t.Errorf("n: got %+v, want %+v", got, tt.want)
+			}
+			if got := schema["s"]; got.Type != tt.str.Type || got.MinLength != tt.str.MinLength || got.MaxLength != tt.str.MaxLength {
+				t.Errorf("s: got %+v, want %+v", got, tt.str)
+			}
+		})
+	}
+
+	if schema := Infer(nil); len(schema) != 0 {
+		t.Errorf("Expected an empty schema for no samples, got %v", schema)
+	}
+}
+
+func TestInferNulls(t *testing.T) {
+	samples := inferSamples(t,
+		`{"a": 1, "b": "x", "tags": ["t", null], "address": {"zip": "12345", "city": null}}`,
+		`{"a": null, "b": "y", "tags": ["u"], "address": {"zip": null, "city": "Lima"}}`,
+		`{"b": "z", "tags": [], "address": {"zip": "54321", "city": "Cusco"}}`,
+	)
+
+	schema := Infer(samples)
+	for _, field := range []string{"a", "address.zip", "address.city"} {
+		parts := strings.Split(field, ".")
+		s := schema
+		if len(parts) == 2 {
+			s = *schema[parts[0]].Schema
+		}
+		if _, ok := s[parts[len(parts)-1]]; ok {
+			t.Errorf("Expected %s, which is sometimes null, to be left out", field)
+		}
+	}
+	if rule := schema["b"]; rule.Type != "string" || !rule.Required {
+		t.Errorf("unexpected rule for b: %+v", rule)
+	}
+	if rule := schema["tags"]; rule.Type != "list" || rule.List != nil {
+		t.Errorf("Expected no List rule for a list holding null, got %+v", rule)
+	}
+	for i, sample := range samples {
+		if result := Validate(sample, schema); !result.IsValid {
+			t.Errorf("sample %d does not pass the inferred schema: %v", i, result.Errors)
+		}
+	}
+}
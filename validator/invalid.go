@@ -0,0 +1,247 @@
+package validator
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// InvalidCase is a document that breaks exactly one constraint of a schema,
+// for testing how handlers reject bad input.
+type InvalidCase struct {
+	Name  string                 // Short description, e.g. "age: below min"
+	Data  map[string]interface{} // The invalid document
+	Field string                 // Expected ValidationError.Field
+	Path  Path                   // Expected ValidationError.Path
+	Code  string                 // Expected ValidationError.Code
+}
+
+// InvalidCases returns minimally invalid documents for schema. See
+// Validator.InvalidCases.
+func InvalidCases(schema Schema, src rand.Source) []InvalidCase {
+	return defaultValidator.InvalidCases(schema, src)
+}
+
+// InvalidCases starts from a valid document with every optional field and
+// at least one item in every list, as Generate would build it, and breaks
+// one constraint at a time: it removes required fields and writes values of
+// the wrong type, out of range, too short or too long, not matching the
+// pattern or format, or not allowed, down into nested maps and list items.
+// A case is only returned if c.Validate reports exactly one error for it,
+// the one named by its Field and Code. Cases come in schema order, and the
+// same src seed gives the same cases. If no valid document can be generated
+// for schema, as described in GenerateDocument, there are no cases.
+func (c *Validator) InvalidCases(schema Schema, src rand.Source) []InvalidCase {
+	g := &generator{Validator: c, rnd: rand.New(src), all: true}
+	base, err := g.schema(schema, 0)
+	if err != nil {
+		return nil
+	}
+	ic := &invalidCases{generator: g, root: schema, base: base}
+	ic.fields(schema, nil, ic.base)
+	if c.rejectUnknown {
+		data := copyValue(ic.base).(map[string]interface{})
+		field := "unexpected_field"
+		for _, exists := schema[field]; exists; _, exists = schema[field] {
+			field += "_"
+		}
+		data[field] = "x"
+		ic.add(field+": unknown field", data, Path{KeySegment(field)}, CodeUnknownField)
+	}
+	return ic.cases
+}
+
+// invalidCases collects the cases of one InvalidCases call.
+type invalidCases struct {
+	*generator
+	root  Schema
+	base  map[string]interface{}
+	cases []InvalidCase
+}
+
+// fields adds the cases for the fields of schema, found at prefix in the
+// base document as data.
+func (ic *invalidCases) fields(schema Schema, prefix Path, data map[string]interface{}) {
+	fields := make([]string, 0, len(schema))
+	for field := range schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		rule, err := ic.registry.resolve(schema[field])
+		if err != nil {
+			continue
+		}
+		path := append(append(Path{}, prefix...), KeySegment(field))
+		if rule.Required {
+			doc := copyValue(ic.base).(map[string]interface{})
+			parent, _ := lookupPath(doc, prefix).(map[string]interface{})
+			delete(parent, field)
+			ic.add(path.String()+": missing", doc, path, CodeRequired)
+		}
+		if value, ok := data[field]; ok {
+			ic.rule(rule, path, value)
+		}
+	}
+}
+
+// rule adds the cases for value, found at path, breaking rule.
+func (ic *invalidCases) rule(rule Rule, path Path, value interface{}) {
+	name := path.String() + ": "
+	set := func(what string, bad interface{}, code string) {
+		doc := copyValue(ic.base).(map[string]interface{})
+		setPath(doc, path, bad)
+		ic.add(name+what, doc, path, code)
+	}
+
+	if bad, ok := wrongTypes[rule.Type]; ok {
+		set("wrong type", bad, CodeTypeMismatch)
+	}
+	switch rule.Type {
+	case "int":
+		if rule.Min != 0 {
+			set("below min", int(int64(rule.Min)-1), CodeMin)
+		}
+		if rule.Max != 0 {
+			set("above max", int(int64(rule.Max)+1), CodeMax)
+		}
+	case "float":
+		if rule.Min != 0 {
+			set("below min", rule.Min-1, CodeMin)
+		}
+		if rule.Max != 0 {
+			set("above max", rule.Max+1, CodeMax)
+		}
+	case "string":
+		s, _ := value.(string)
+		if rule.MinLength > 0 {
+			set("too short", padString(s, rule.MinLength-1), CodeMinLength)
+		}
+		if rule.MaxLength > 0 {
+			set("too long", padString(s, rule.MaxLength+1), CodeMaxLength)
+		}
+		if rule.Regex != nil {
+			if bad, ok := badString(rule, rule.Regex.MatchString); ok {
+				set("pattern mismatch", bad, CodePattern)
+			}
+		}
+		if fn := ic.format(rule.Format); fn != nil {
+			if bad, ok := badString(rule, fn); ok {
+				set("invalid "+rule.Format, bad, CodeFormat)
+			}
+		}
+	case "list":
+		if list, ok := value.([]interface{}); ok && len(list) > 0 && rule.List != nil {
+			ic.rule(*rule.List, append(append(Path{}, path...), IndexSegment(0)), list[0])
+		}
+	case "map":
+		if m, ok := value.(map[string]interface{}); ok && rule.Schema != nil {
+			ic.fields(*rule.Schema, path, m)
+		}
+	}
+
+	if len(rule.Allowed) > 0 {
+		open := rule
+		open.Allowed, open.Default = nil, nil
+		for attempt := 0; attempt < generateAttempts; attempt++ {
+			if bad := ic.value(open, len(path)); bad != nil && !isAllowed(bad, rule.Allowed) {
+				set("not allowed", bad, CodeAllowed)
+				break
+			}
+		}
+	}
+}
+
+// add records a case if validating data gives exactly the expected error.
+func (ic *invalidCases) add(name string, data map[string]interface{}, path Path, code string) {
+	result := ic.Validate(data, ic.root)
+	if len(result.Errors) != 1 || result.Errors[0].Field != path.String() || result.Errors[0].Code != code {
+		return
+	}
+	ic.cases = append(ic.cases, InvalidCase{Name: name, Data: data, Field: path.String(), Path: path, Code: code})
+}
+
+// wrongTypes holds a value of another type for each built-in type.
+var wrongTypes = map[string]interface{}{
+	"string": 12345,
+	"int":    "not a number",
+	"float":  "not a number",
+	"bool":   "true",
+	"list":   "not a list",
+	"map":    "not a map",
+}
+
+// padString cuts or pads s with 'a' to n bytes.
+func padString(s string, n int) string {
+	if len(s) >= n {
+		return s[:n]
+	}
+	return s + strings.Repeat("a", n-len(s))
+}
+
+// badString returns a string that fails ok while keeping to the lengths of
+// rule.
+func badString(rule Rule, ok func(string) bool) (string, bool) {
+	n := rule.MinLength
+	if n == 0 {
+		n = 1
+	}
+	for _, c := range []string{"!", " ", "0", "a", "A", "_", "~"} {
+		if s := strings.Repeat(c, n); !ok(s) {
+			return s, true
+		}
+	}
+	if rule.MinLength == 0 && !ok("") {
+		return "", true
+	}
+	return "", false
+}
+
+// copyValue deep-copies the maps and lists in value.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = copyValue(item)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = copyValue(item)
+		}
+		return list
+	}
+	return value
+}
+
+// lookupPath returns the value at path in data, or nil.
+func lookupPath(data interface{}, path Path) interface{} {
+	for _, seg := range path {
+		switch v := data.(type) {
+		case map[string]interface{}:
+			data = v[seg.Key]
+		case []interface{}:
+			if seg.Index >= len(v) {
+				return nil
+			}
+			data = v[seg.Index]
+		default:
+			return nil
+		}
+	}
+	return data
+}
+
+// setPath replaces the value at path in data, whose parents must exist.
+func setPath(data map[string]interface{}, path Path, value interface{}) {
+	last := path[len(path)-1]
+	switch parent := lookupPath(data, path[:len(path)-1]).(type) {
+	case map[string]interface{}:
+		parent[last.Key] = value
+	case []interface{}:
+		parent[last.Index] = value
+	}
+}
@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestInvalidCases(t *testing.T) {
+	schema := Schema{
+		"name":   {Type: "string", Required: true, MinLength: 2, MaxLength: 5},
+		"age":    {Type: "int", Min: 18, Max: 99},
+		"role":   {Type: "string", Allowed: []interface{}{"admin", "user"}},
+		"email":  {Type: "string", Format: "email"},
+		"code":   {Type: "string", Regex: regexp.MustCompile(`^[A-Z]{3}$`)},
+		"active": {Type: "bool"},
+		"orders": {Type: "list", Required: true, List: &Rule{Type: "map", Schema: &Schema{
+			"qty": {Type: "float", Required: true, Min: 0.5},
+		}}},
+	}
+
+	cases := InvalidCases(schema, rand.NewSource(1))
+	var got []string
+	for _, c := range cases {
+		got = append(got, c.Name+" => "+c.Field+" "+c.Code)
+
+		result := Validate(c.Data, schema)
+		if len(result.Errors) != 1 || result.Errors[0].Field != c.Field || result.Errors[0].Code != c.Code {
+			t.Errorf("%s: expected one %s error at %s, got %v", c.Name, c.Code, c.Field, result.Errors)
+		}
+		if c.Path.String() != c.Field {
+			t.Errorf("%s: path %v does not match field %s", c.Name, c.Path, c.Field)
+		}
+	}
+	want := []string{
+		"active: wrong type => active type_mismatch",
+		"age: wrong type => age type_mismatch",
+		"age: below min => age min",
+		"age: above max => age max",
+		"code: wrong type => code type_mismatch",
+		"code: pattern mismatch => code pattern",
+		"email: wrong type => email type_mismatch",
+		"email: invalid email => email format",
+		"name: missing => name required",
+		"name: wrong type => name type_mismatch",
+		"name: too short => name min_length",
+		"name: too long => name max_length",
+		"orders: missing => orders required",
+		"orders: wrong type => orders type_mismatch",
+		"orders[0]: wrong type => orders[0] type_mismatch",
+		"orders[0].qty: missing => orders[0].qty required",
+		"orders[0].qty: wrong type => orders[0].qty type_mismatch",
+		"orders[0].qty: below min => orders[0].qty min",
+		"role: wrong type => role type_mismatch",
+		"role: not allowed => role allowed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got cases:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if again := InvalidCases(schema, rand.NewSource(1)); !reflect.DeepEqual(again, cases) {
+		t.Errorf("Expected the same cases for the same seed")
+	}
+
+	strict := New(WithAllowUnknown(false)).InvalidCases(Schema{"id": {Type: "int"}}, rand.NewSource(1))
+	if last := strict[len(strict)-1]; last.Code != CodeUnknownField || last.Field != "unexpected_field" {
+		t.Errorf("Expected an unknown field case, got %+v", strict)
+	}
+}
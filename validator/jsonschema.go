@@ -0,0 +1,294 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSONSchemaDraft is the dialect emitted by ToJSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Warning reports a construct that could not be translated exactly between
+// go-schema and another schema language.
+type Warning struct {
+	Path    string
+	Message string
+}
+
+// String returns a string representation of the warning
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Path, w.Message)
+}
+
+// jsonSchemaTypes maps go-schema types to JSON Schema types.
+var jsonSchemaTypes = map[string]string{
+	"string": "string",
+	"int":    "integer",
+	"float":  "number",
+	"bool":   "boolean",
+	"list":   "array",
+	"map":    "object",
+}
+
+// ToJSONSchema translates schema into a JSON Schema (draft 2020-12) document
+// describing an object with one property per field.
+//
+// Rules that cannot be expressed in JSON Schema are not dropped silently:
+// the affected node carries a "$comment" explaining what was lost. Use
+// ExportJSONSchema to receive the same problems as a list of warnings.
+func ToJSONSchema(schema Schema) ([]byte, error) {
+	data, _, err := ExportJSONSchema(schema)
+	return data, err
+}
+
+// ExportJSONSchema is like ToJSONSchema but also returns a warning for every
+// construct that has no exact JSON Schema equivalent.
+func ExportJSONSchema(schema Schema) ([]byte, []Warning, error) {
+	return exportJSONSchema(schema, nil)
+}
+
+// ToJSONSchema is like the package-level ToJSONSchema but also emits every
+// definition reachable through Ref rules under "$defs".
+func (r *Registry) ToJSONSchema(schema Schema) ([]byte, error) {
+	data, _, err := r.ExportJSONSchema(schema)
+	return data, err
+}
+
+// ExportJSONSchema is like the package-level ExportJSONSchema but also emits
+// every definition reachable through Ref rules under "$defs".
+func (r *Registry) ExportJSONSchema(schema Schema) ([]byte, []Warning, error) {
+	return exportJSONSchema(schema, r)
+}
+
+func exportJSONSchema(schema Schema, registry *Registry) ([]byte, []Warning, error) {
+	e := &jsonSchemaExporter{registry: registry, refPrefix: "#/$defs/"}
+	doc := e.object(schema, "")
+	doc["$schema"] = JSONSchemaDraft
+	if len(e.defs) > 0 {
+		doc["$defs"] = e.defs
+	}
+
+	data, err := marshalIndent(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, e.warnings, nil
+}
+
+// jsonSchemaExporter accumulates warnings and referenced definitions while
+// translating a schema.
+type jsonSchemaExporter struct {
+	registry  *Registry
+	refPrefix string                 // Prefix turning a Ref name into a JSON pointer
+	defs      map[string]interface{} // Translated definitions by name
+	warnings  []Warning
+}
+
+// reference translates the definition named by a Ref into e.defs once. The
+// entry is reserved before translating so that recursive definitions end.
+func (e *jsonSchemaExporter) reference(node map[string]interface{}, path, name string) {
+	if _, done := e.defs[name]; done {
+		return
+	}
+	if e.registry == nil {
+		e.warn(node, path, fmt.Sprintf("reference '%s' cannot be resolved without a registry", name))
+		return
+	}
+	target, ok := e.registry.Lookup(name)
+	if !ok {
+		e.warn(node, path, fmt.Sprintf("unresolved reference '%s'", name))
+		return
+	}
+
+	if e.defs == nil {
+		e.defs = make(map[string]interface{})
+	}
+	e.defs[name] = nil
+	e.defs[name] = e.rule(target, name)
+}
+
+// warn records a warning and attaches it to node as a $comment.
+func (e *jsonSchemaExporter) warn(node map[string]interface{}, path, msg string) {
+	e.warnings = append(e.warnings, Warning{Path: path, Message: msg})
+	if prev, ok := node["$comment"].(string); ok {
+		msg = prev + "; " + msg
+	}
+	node["$comment"] = msg
+}
+
+// object translates a schema into an object node.
+func (e *jsonSchemaExporter) object(schema Schema, prefix string) map[string]interface{} {
+	node := map[string]interface{}{"type": "object"}
+
+	fields := make([]string, 0, len(schema))
+	for field := range schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	properties := make(map[string]interface{}, len(schema))
+	var required []string
+	for _, field := range fields {
+		rule := schema[field]
+		properties[field] = e.rule(rule, prefix+field)
+		if e.required(rule) {
+			required = append(required, field)
+		}
+	}
+	node["properties"] = properties
+	if len(required) > 0 {
+		node["required"] = required
+	}
+	return node
+}
+
+// required reports whether Validate requires a field with rule, which it
+// also does when the definition a Ref names is Required.
+func (e *jsonSchemaExporter) required(rule Rule) bool {
+	if rule.Required || rule.Ref == "" || e.registry == nil {
+		return rule.Required
+	}
+	resolved, err := e.registry.resolve(rule)
+	return err == nil && resolved.Required
+}
+
+// rule translates a single rule. path identifies the rule in warnings.
+func (e *jsonSchemaExporter) rule(rule Rule, path string) map[string]interface{} {
+	node := map[string]interface{}{}
+
+	if rule.Ref != "" {
+		node["$ref"] = e.refPrefix + rule.Ref
+		e.reference(node, path, rule.Ref)
+		if rule.Default != nil {
+			node["default"] = rule.Default
+		}
+		e.metadata(node, rule)
+		if rule.Messages != nil {
+			e.warn(node, path, "custom error messages have no JSON Schema equivalent")
+		}
+		return node
+	}
+
+	if typ, ok := jsonSchemaTypes[rule.Type]; ok {
+		node["type"] = typ
+	} else {
+		e.warn(node, path, fmt.Sprintf("type '%s' has no JSON Schema equivalent", rule.Type))
+	}
+
+	if rule.Default != nil {
+		node["default"] = rule.Default
+	}
+	e.metadata(node, rule)
+
+	if rule.Allowed != nil {
+		node["enum"] = rule.Allowed
+	}
+
+	if rule.Min != 0 || rule.Max != 0 {
+		if rule.Type == "int" || rule.Type == "float" {
+			if rule.Min != 0 {
+				node["minimum"] = rule.Min
+			}
+			if rule.Max != 0 {
+				node["maximum"] = rule.Max
+			}
+		} else {
+			e.warn(node, path, "min/max ignored on non-numeric type")
+		}
+	}
+
+	pattern := rule.RegexPattern
+	if rule.Regex != nil {
+		pattern = rule.Regex.String()
+	}
+	if rule.MinLength != 0 || rule.MaxLength != 0 || pattern != "" {
+		if rule.Type == "string" {
+			if rule.MinLength != 0 {
+				node["minLength"] = rule.MinLength
+			}
+			if rule.MaxLength != 0 {
+				node["maxLength"] = rule.MaxLength
+			}
+			if pattern != "" {
+				node["pattern"] = pattern
+				if hint := re2OnlySyntax(pattern); hint != "" {
+					e.warn(node, path, fmt.Sprintf("pattern uses RE2 syntax %s that ECMA-262 validators may reject", hint))
+				}
+			}
+		} else {
+			e.warn(node, path, "length and regex constraints ignored on non-string type")
+		}
+	}
+
+	if rule.Format != "" {
+		if rule.Type == "string" {
+			node["format"] = rule.Format
+		} else {
+			e.warn(node, path, "format ignored on non-string type")
+		}
+	}
+
+	if rule.Check != "" {
+		e.warn(node, path, fmt.Sprintf("check '%s' has no JSON Schema equivalent", rule.Check))
+	}
+	if rule.Severity == SeverityWarning || len(rule.Warn) > 0 {
+		e.warn(node, path, "warning severities have no JSON Schema equivalent; the constraints are exported as errors")
+	}
+
+	if rule.List != nil {
+		if rule.Type == "list" {
+			node["items"] = e.rule(*rule.List, path+"[]")
+		} else {
+			e.warn(node, path, "list rule ignored on non-list type")
+		}
+	}
+
+	if rule.Schema != nil {
+		if rule.Type == "map" {
+			for k, v := range e.object(*rule.Schema, path+".") {
+				node[k] = v
+			}
+		} else {
+			e.warn(node, path, "nested schema ignored on non-map type")
+		}
+	}
+
+	if rule.Messages != nil {
+		e.warn(node, path, "custom error messages have no JSON Schema equivalent")
+	}
+
+	return node
+}
+
+// deprecationReasonKeyword carries Rule.Deprecated, since the JSON Schema
+// "deprecated" keyword is a plain boolean.
+const deprecationReasonKeyword = "x-deprecated-reason"
+
+// metadata copies the documentation fields of rule to node.
+func (e *jsonSchemaExporter) metadata(node map[string]interface{}, rule Rule) {
+	if rule.Title != "" {
+		node["title"] = rule.Title
+	}
+	if rule.Description != "" {
+		node["description"] = rule.Description
+	}
+	if rule.Examples != nil {
+		node["examples"] = rule.Examples
+	}
+	if rule.Deprecated != "" {
+		node["deprecated"] = true
+		node[deprecationReasonKeyword] = rule.Deprecated
+	}
+}
+
+// re2OnlySyntax returns the first Go RE2 construct in pattern that is not
+// part of ECMA-262 regular expressions, or "" if none is found.
+func re2OnlySyntax(pattern string) string {
+	for _, construct := range []string{`(?P<`, `\A`, `\z`, `[[:`, `(?i`, `(?m`, `(?s`, `(?U`, `\pL`, `\PL`} {
+		if strings.Contains(pattern, construct) {
+			return fmt.Sprintf("%q", construct)
+		}
+	}
+	return ""
+}
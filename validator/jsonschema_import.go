@@ -0,0 +1,364 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// goSchemaTypes maps JSON Schema types to go-schema types.
+var goSchemaTypes = map[string]string{
+	"string":  "string",
+	"integer": "int",
+	"number":  "float",
+	"boolean": "bool",
+	"array":   "list",
+	"object":  "map",
+}
+
+// ignoredJSONSchemaKeywords are annotations and structural keywords that do
+// not affect validation and are skipped without a warning.
+var ignoredJSONSchemaKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$defs": true, "definitions": true,
+	"readOnly": true, "writeOnly": true,
+}
+
+// FromJSONSchema translates a JSON Schema document describing an object into
+// a Schema. It understands type, properties, required, items, minimum,
+// maximum, minLength, maxLength, pattern, format, default, enum, const, title,
+// description, examples and deprecated, and
+// resolves local "$ref" pointers such as "#/$defs/address".
+//
+// Keywords without a go-schema equivalent are listed as warnings rather than
+// failing the import. An error is returned for malformed documents, remote
+// or recursive references, invalid patterns and roots that are not objects.
+func FromJSONSchema(data []byte) (Schema, []Warning, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	if root == nil {
+		return nil, nil, fmt.Errorf("invalid JSON Schema: document must be an object")
+	}
+
+	im := &jsonSchemaImporter{root: root}
+	rule, err := im.rule(root, "", nil)
+	if err != nil {
+		return nil, im.warnings, err
+	}
+	if rule.Type != "map" {
+		return nil, im.warnings, fmt.Errorf("root of JSON Schema must describe an object, got %q", rule.Type)
+	}
+	if rule.Schema == nil {
+		return Schema{}, im.warnings, nil
+	}
+	return *rule.Schema, im.warnings, nil
+}
+
+// jsonSchemaImporter accumulates warnings while translating a document.
+type jsonSchemaImporter struct {
+	root     map[string]interface{}
+	warnings []Warning
+}
+
+func (im *jsonSchemaImporter) warn(path, format string, args ...interface{}) {
+	im.warnings = append(im.warnings, Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolve follows a local "$ref" JSON pointer from the document root.
+func (im *jsonSchemaImporter) resolve(ref string) (map[string]interface{}, error) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("only local $ref pointers are supported, got %q", ref)
+	}
+
+	var node interface{} = im.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		if node, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("$ref %q does not point to a schema", ref)
+	}
+	return obj, nil
+}
+
+// rule translates one schema node. refs holds the references being resolved
+// on the current branch so that recursive definitions are detected.
+func (im *jsonSchemaImporter) rule(node map[string]interface{}, path string, refs []string) (Rule, error) {
+	if ref, ok := node["$ref"].(string); ok {
+		for _, seen := range refs {
+			if seen == ref {
+				return Rule{}, fmt.Errorf("%s: recursive $ref %q is not supported", displayPath(path), ref)
+			}
+		}
+		target, err := im.resolve(ref)
+		if err != nil {
+			return Rule{}, fmt.Errorf("%s: %v", displayPath(path), err)
+		}
+		// Keywords next to $ref apply in addition to the referenced schema.
+		merged := make(map[string]interface{}, len(target)+len(node))
+		for k, v := range target {
+			merged[k] = v
+		}
+		for k, v := range node {
+			if k != "$ref" {
+				merged[k] = v
+			}
+		}
+		return im.rule(merged, path, append(refs[:len(refs):len(refs)], ref))
+	}
+
+	var rule Rule
+	var err error
+	if rule.Type, err = im.ruleType(node, path); err != nil {
+		return Rule{}, err
+	}
+
+	keywords := make([]string, 0, len(node))
+	for k := range node {
+		keywords = append(keywords, k)
+	}
+	sort.Strings(keywords)
+
+	for _, k := range keywords {
+		v := node[k]
+		switch k {
+		case "type", "required":
+			// Handled by ruleType and by the parent object.
+		case "minimum", "maximum":
+			n, ok := v.(float64)
+			if !ok {
+				return Rule{}, fmt.Errorf("%s: %s must be a number", displayPath(path), k)
+			}
+			if n == 0 {
+				im.warn(path, "%s of 0 cannot be expressed and was dropped", k)
+			} else if k == "minimum" {
+				rule.Min = n
+			} else {
+				rule.Max = n
+			}
+		case "minLength", "maxLength":
+			n, ok := v.(float64)
+			if !ok || n != float64(int(n)) || n < 0 {
+				return Rule{}, fmt.Errorf("%s: %s must be a non-negative integer", displayPath(path), k)
+			}
+			if k == "minLength" {
+				rule.MinLength = int(n)
+			} else {
+				rule.MaxLength = int(n)
+			}
+		case "pattern":
+			pattern, ok := v.(string)
+			if !ok {
+				return Rule{}, fmt.Errorf("%s: pattern must be a string", displayPath(path))
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return Rule{}, fmt.Errorf("%s: invalid pattern: %v", displayPath(path), err)
+			}
+			rule.RegexPattern = pattern
+			rule.Regex = re
+		case "format":
+			format, ok := v.(string)
+			if !ok {
+				return Rule{}, fmt.Errorf("%s: format must be a string", displayPath(path))
+			}
+			if rule.Type != "string" || builtinFormats[format] == nil {
+				im.warn(path, "format %q is not supported and was dropped", format)
+				continue
+			}
+			rule.Format = format
+		case "title", "description":
+			text, ok := v.(string)
+			if !ok {
+				return Rule{}, fmt.Errorf("%s: %s must be a string", displayPath(path), k)
+			}
+			if k == "title" {
+				rule.Title = text
+			} else {
+				rule.Description = text
+			}
+		case "examples":
+			examples, ok := v.([]interface{})
+			if !ok {
+				return Rule{}, fmt.Errorf("%s: examples must be an array", displayPath(path))
+			}
+			rule.Examples = examples
+		case "deprecated":
+			if deprecated, ok := v.(bool); !ok {
+				return Rule{}, fmt.Errorf("%s: deprecated must be a boolean", displayPath(path))
+			} else if deprecated {
+				rule.Deprecated = "deprecated"
+				if reason, ok := node[deprecationReasonKeyword].(string); ok && reason != "" {
+					rule.Deprecated = reason
+				}
+			}
+		case deprecationReasonKeyword:
+			// Read together with "deprecated".
+		case "default":
+			rule.Default = v
+		case "enum":
+			values, ok := v.([]interface{})
+			if !ok {
+				return Rule{}, fmt.Errorf("%s: enum must be an array", displayPath(path))
+			}
+			rule.Allowed = values
+		case "const":
+			rule.Allowed = []interface{}{v}
+		case "items":
+			items, ok := v.(map[string]interface{})
+			if !ok {
+				im.warn(path, "items must be a single schema; %T form is not supported", v)
+				continue
+			}
+			list, err := im.rule(items, path+"[]", refs)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.List = &list
+		case "properties":
+			props, ok := v.(map[string]interface{})
+			if !ok {
+				return Rule{}, fmt.Errorf("%s: properties must be an object", displayPath(path))
+			}
+			schema, err := im.properties(props, node["required"], path, refs)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Schema = &schema
+		default:
+			if !ignoredJSONSchemaKeywords[k] {
+				im.warn(path, "unsupported keyword %q", k)
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+// properties translates an object's properties and required list.
+func (im *jsonSchemaImporter) properties(props map[string]interface{}, required interface{}, path string, refs []string) (Schema, error) {
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	fields := make([]string, 0, len(props))
+	for field := range props {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	schema := make(Schema, len(props))
+	for _, field := range fields {
+		node, ok := props[field].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: property schema must be an object", prefix+field)
+		}
+		rule, err := im.rule(node, prefix+field, refs)
+		if err != nil {
+			return nil, err
+		}
+		schema[field] = rule
+	}
+
+	names, _ := required.([]interface{})
+	for _, name := range names {
+		field, _ := name.(string)
+		rule, ok := schema[field]
+		if !ok {
+			im.warn(path, "required property %q is not defined in properties", field)
+			continue
+		}
+		rule.Required = true
+		schema[field] = rule
+	}
+	return schema, nil
+}
+
+// ruleType determines the go-schema type of a node, inferring it from other
+// keywords when "type" is missing.
+func (im *jsonSchemaImporter) ruleType(node map[string]interface{}, path string) (string, error) {
+	var types []string
+	switch t := node["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+	case nil:
+		return im.inferType(node, path)
+	default:
+		return "", fmt.Errorf("%s: type must be a string or an array", displayPath(path))
+	}
+
+	var mapped []string
+	for _, t := range types {
+		if t == "null" {
+			im.warn(path, "null is not supported and was dropped from the type")
+			continue
+		}
+		goType, ok := goSchemaTypes[t]
+		if !ok {
+			return "", fmt.Errorf("%s: unknown type %q", displayPath(path), t)
+		}
+		mapped = append(mapped, goType)
+	}
+	switch len(mapped) {
+	case 0:
+		return "", fmt.Errorf("%s: no supported type in %v", displayPath(path), types)
+	case 1:
+		return mapped[0], nil
+	default:
+		im.warn(path, "union types are not supported; using %q", mapped[0])
+		return mapped[0], nil
+	}
+}
+
+func (im *jsonSchemaImporter) inferType(node map[string]interface{}, path string) (string, error) {
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := node[k]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var typ string
+	switch {
+	case has("properties", "required"):
+		typ = "map"
+	case has("items"):
+		typ = "list"
+	case has("pattern", "minLength", "maxLength"):
+		typ = "string"
+	case has("minimum", "maximum"):
+		typ = "float"
+	default:
+		return "", fmt.Errorf("%s: cannot determine type without a \"type\" keyword", displayPath(path))
+	}
+	im.warn(path, "missing type, assuming %q", typ)
+	return typ, nil
+}
+
+// displayPath renders an empty path as the document root in messages.
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,192 @@
+package validator
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFromJSONSchema(t *testing.T) {
+	doc := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "User",
+		"type": "object",
+		"required": ["name", "address"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 50, "pattern": "^[A-Z]"},
+			"age": {"type": "integer", "minimum": 18, "maximum": 99},
+			"role": {"type": "string", "enum": ["admin", "user"], "default": "user"},
+			"kind": {"const": "person", "type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"address": {"$ref": "#/$defs/address"},
+			"billing": {"$ref": "#/$defs/address", "description": "Billing address"}
+		},
+		"$defs": {
+			"address": {
+				"type": "object",
+				"required": ["zip"],
+				"properties": {"zip": {"type": "string"}, "city": {"type": "string"}}
+			}
+		}
+	}`
+
+	schema, warnings, err := FromJSONSchema([]byte(doc))
+	if err != nil {
+		t.Fatalf("FromJSONSchema returned error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	name := schema["name"]
+	if name.Type != "string" || !name.Required || name.MinLength != 2 || name.MaxLength != 50 || name.Regex == nil {
+		t.Errorf("unexpected 'name' rule: %+v", name)
+	}
+	if age := schema["age"]; age.Type != "int" || age.Min != 18 || age.Max != 99 || age.Required {
+		t.Errorf("unexpected 'age' rule: %+v", age)
+	}
+	if role := schema["role"]; len(role.Allowed) != 2 || role.Default != "user" {
+		t.Errorf("unexpected 'role' rule: %+v", role)
+	}
+	if kind := schema["kind"]; len(kind.Allowed) != 1 || kind.Allowed[0] != "person" {
+		t.Errorf("unexpected 'kind' rule: %+v", kind)
+	}
+	if tags := schema["tags"]; tags.Type != "list" || tags.List == nil || tags.List.Type != "string" {
+		t.Errorf("unexpected 'tags' rule: %+v", tags)
+	}
+	address := schema["address"]
+	if address.Type != "map" || !address.Required || address.Schema == nil || !(*address.Schema)["zip"].Required {
+		t.Errorf("unexpected 'address' rule: %+v", address)
+	}
+	if billing := schema["billing"]; billing.Required || billing.Schema == nil {
+		t.Errorf("unexpected 'billing' rule: %+v", billing)
+	}
+
+	if err := ValidateSchema(schema); err != nil {
+		t.Errorf("Imported schema is not valid: %v", err)
+	}
+}
+
+func TestFromJSONSchemaWarnings(t *testing.T) {
+	doc := `{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["ghost"],
+		"properties": {
+			"nickname": {"type": ["string", "null"]},
+			"count": {"type": "integer", "minimum": 0, "multipleOf": 2},
+			"pair": {"type": "array", "items": [{"type": "string"}]},
+			"code": {"pattern": "^[0-9]+$"}
+		}
+	}`
+
+	schema, warnings, err := FromJSONSchema([]byte(doc))
+	if err != nil {
+		t.Fatalf("FromJSONSchema returned error: %v", err)
+	}
+
+	want := []Warning{
+		{Path: "", Message: `unsupported keyword "additionalProperties"`},
+		{Path: "", Message: `required property "ghost" is not defined in properties`},
+	}
+	for _, w := range want {
+		if !containsWarning(warnings, w) {
+			t.Errorf("Missing warning %v in %v", w, warnings)
+		}
+	}
+	for _, path := range []string{"nickname", "count", "pair", "code"} {
+		if !hasWarningAt(warnings, path) {
+			t.Errorf("Expected a warning for %q, got %v", path, warnings)
+		}
+	}
+	if schema["nickname"].Type != "string" || schema["code"].Type != "string" {
+		t.Errorf("unexpected types: %+v", schema)
+	}
+}
+
+func TestFromJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{name: "Malformed JSON", doc: `{"type": "object"`},
+		{name: "Not an object document", doc: `[]`},
+		{name: "Root not an object", doc: `{"type": "string"}`},
+		{name: "Remote ref", doc: `{"type": "object", "properties": {"a": {"$ref": "https://example.com/a.json"}}}`},
+		{name: "Dangling ref", doc: `{"type": "object", "properties": {"a": {"$ref": "#/$defs/missing"}}}`},
+		{name: "Recursive ref", doc: `{"$defs": {"node": {"type": "object", "properties": {"child": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`},
+		{name: "Invalid pattern", doc: `{"type": "object", "properties": {"a": {"type": "string", "pattern": "(["}}}`},
+		{name: "Unknown type", doc: `{"type": "object", "properties": {"a": {"type": "decimal"}}}`},
+		{name: "Untyped property", doc: `{"type": "object", "properties": {"a": {}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := FromJSONSchema([]byte(tt.doc)); err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestJSONSchemaRoundTrip(t *testing.T) {
+	schemas := []Schema{
+		{
+			"name":   {Type: "string", Required: true, MinLength: 2, MaxLength: 50},
+			"email":  {Type: "string", RegexPattern: `^[^@]+@[^@]+$`, Regex: regexp.MustCompile(`^[^@]+@[^@]+$`)},
+			"age":    {Type: "int", Min: 18, Max: 99},
+			"price":  {Type: "float", Min: 0.5, Default: 1.5},
+			"active": {Type: "bool", Default: true},
+			"color":  {Type: "string", Allowed: []interface{}{"red", "green"}},
+			"site":   {Type: "string", Format: "uri"},
+			"fax": {Type: "string", Title: "Fax", Description: "Fax number", Examples: []interface{}{"+1 555 0100"},
+				Deprecated: "use phone"},
+		},
+		{
+			"tags": {Type: "list", List: &Rule{Type: "string", MaxLength: 10}},
+			"data": {
+				Type:     "map",
+				Required: true,
+				Schema: &Schema{
+					"users": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+						"id": {Type: "int", Required: true},
+					}}},
+				},
+			},
+		},
+	}
+
+	for i, original := range schemas {
+		exported, warnings, err := ExportJSONSchema(original)
+		if err != nil || len(warnings) != 0 {
+			t.Fatalf("schema %d: export failed: %v %v", i, err, warnings)
+		}
+		imported, warnings, err := FromJSONSchema(exported)
+		if err != nil || len(warnings) != 0 {
+			t.Fatalf("schema %d: import failed: %v %v", i, err, warnings)
+		}
+
+		want, _ := MarshalSchema(original)
+		got, _ := MarshalSchema(imported)
+		if string(got) != string(want) {
+			t.Errorf("schema %d: round trip mismatch\ngot:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+func containsWarning(warnings []Warning, want Warning) bool {
+	for _, w := range warnings {
+		if w == want {
+			return true
+		}
+	}
+	return false
+}
+
+func hasWarningAt(warnings []Warning, path string) bool {
+	for _, w := range warnings {
+		if w.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestToJSONSchema(t *testing.T) {
+	schema := Schema{
+		"name":   {Type: "string", Required: true, MinLength: 2, MaxLength: 50},
+		"age":    {Type: "int", Min: 18, Max: 99},
+		"price":  {Type: "float", Min: 0.5},
+		"active": {Type: "bool", Default: true},
+		"email":  {Type: "string", Regex: regexp.MustCompile(`^[^@]+@[^@]+$`)},
+		"tags":   {Type: "list", List: &Rule{Type: "string", RegexPattern: "^[a-z]+$"}},
+		"address": {
+			Type:     "map",
+			Required: true,
+			Schema: &Schema{
+				"zip":  {Type: "string", Required: true},
+				"city": {Type: "string"},
+			},
+		},
+	}
+
+	got, err := ToJSONSchema(schema)
+	if err != nil {
+		t.Fatalf("ToJSONSchema returned error: %v", err)
+	}
+
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "active": {
+      "default": true,
+      "type": "boolean"
+    },
+    "address": {
+      "properties": {
+        "city": {
+          "type": "string"
+        },
+        "zip": {
+          "type": "string"
+        }
+      },
+      "required": [
+        "zip"
+      ],
+      "type": "object"
+    },
+    "age": {
+      "maximum": 99,
+      "minimum": 18,
+      "type": "integer"
+    },
+    "email": {
+      "pattern": "^[^@]+@[^@]+$",
+      "type": "string"
+    },
+    "name": {
+      "maxLength": 50,
+      "minLength": 2,
+      "type": "string"
+    },
+    "price": {
+      "minimum": 0.5,
+      "type": "number"
+    },
+    "tags": {
+      "items": {
+        "pattern": "^[a-z]+$",
+        "type": "string"
+      },
+      "type": "array"
+    }
+  },
+  "required": [
+    "address",
+    "name"
+  ],
+  "type": "object"
+}
+`
+	if string(got) != want {
+		t.Errorf("ToJSONSchema() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportJSONSchemaWarnings(t *testing.T) {
+	schema := Schema{
+		"custom":   {Type: "money"},
+		"username": {Type: "string", Min: 3},
+		"count":    {Type: "int", MaxLength: 3, Schema: &Schema{}},
+		"code":     {Type: "string", RegexPattern: `(?P<id>\d+)`},
+		"nested": {Type: "list", List: &Rule{
+			Type:     "string",
+			Messages: &Messages{Required: strPtr("needed")},
+		}},
+	}
+
+	data, warnings, err := ExportJSONSchema(schema)
+	if err != nil {
+		t.Fatalf("ExportJSONSchema returned error: %v", err)
+	}
+
+	wantPaths := []string{"code", "count", "count", "custom", "nested[]", "username"}
+	if len(warnings) != len(wantPaths) {
+		t.Fatalf("Expected %d warnings, got %v", len(wantPaths), warnings)
+	}
+	for i, w := range warnings {
+		if w.Path != wantPaths[i] {
+			t.Errorf("warning %d: Path = %q, want %q (%s)", i, w.Path, wantPaths[i], w)
+		}
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	count := doc["properties"].(map[string]interface{})["count"].(map[string]interface{})
+	comment, _ := count["$comment"].(string)
+	if !strings.Contains(comment, "non-string") || !strings.Contains(comment, "non-map") {
+		t.Errorf("Expected both warnings in $comment, got %q", comment)
+	}
+	if _, ok := count["maxLength"]; ok {
+		t.Errorf("Expected maxLength to be left out on an int field")
+	}
+}
+
+func TestWarningString(t *testing.T) {
+	w := Warning{Path: "a.b", Message: "lost"}
+	if w.String() != "a.b: lost" {
+		t.Errorf("String() = %q", w.String())
+	}
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+)
+
+// ParseSchema decodes a schema from its JSON representation and compiles the
+// regex pattern of every rule. Unknown rule keys are rejected so that typos
+// such as "requred" do not go unnoticed.
+func ParseSchema(data []byte) (Schema, error) {
+	return LoadSchema(bytes.NewReader(data))
+}
+
+// LoadSchema reads a JSON schema from r. See ParseSchema.
+func LoadSchema(r io.Reader) (Schema, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var schema Schema
+	if err := dec.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid schema JSON: unexpected data after schema")
+	}
+	if err := compileSchema(schema, ""); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// LoadSchemaFile reads a JSON schema from the named file. See ParseSchema.
+func LoadSchemaFile(path string) (Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	schema, err := LoadSchema(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// MarshalSchema returns the canonical JSON form of schema: two-space
+// indentation, fields in sorted order and a trailing newline.
+func MarshalSchema(schema Schema) ([]byte, error) {
+	return marshalIndent(schema)
+}
+
+// marshalIndent encodes v as indented JSON without escaping HTML characters,
+// which are common in regex patterns.
+func marshalIndent(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compileSchema compiles RegexPattern into Regex for every rule in schema.
+func compileSchema(schema Schema, prefix string) error {
+	for field, rule := range schema {
+		if err := compileRule(&rule, prefix+field); err != nil {
+			return err
+		}
+		schema[field] = rule
+	}
+	return nil
+}
+
+func compileRule(rule *Rule, path string) error {
+	if rule.RegexPattern != "" && rule.Regex == nil {
+		re, err := regexp.Compile(rule.RegexPattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex for '%s': %v", path, err)
+		}
+		rule.Regex = re
+	}
+	if rule.List != nil {
+		if err := compileRule(rule.List, path+"[]"); err != nil {
+			return err
+		}
+	}
+	if rule.Schema != nil {
+		if err := compileSchema(*rule.Schema, path+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
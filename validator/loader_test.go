@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	data := `{
+		"email": {"type": "string", "required": true, "regex": "^[^@]+@[^@]+$"},
+		"tags": {"type": "list", "list": {"type": "string", "regex": "^[a-z]+$"}},
+		"address": {"type": "map", "schema": {"zip": {"type": "string", "regex": "^[0-9]{5}$"}}}
+	}`
+
+	schema, err := ParseSchema([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseSchema returned error: %v", err)
+	}
+
+	if schema["email"].Regex == nil || !schema["email"].Required {
+		t.Errorf("Expected compiled regex and required flag on 'email'")
+	}
+	if schema["tags"].List.Regex == nil {
+		t.Errorf("Expected compiled regex on list items")
+	}
+	if (*schema["address"].Schema)["zip"].Regex == nil {
+		t.Errorf("Expected compiled regex on nested schema")
+	}
+
+	result := Validate(map[string]interface{}{
+		"email":   "nobody",
+		"tags":    []interface{}{"Go"},
+		"address": map[string]interface{}{"zip": "abc"},
+	}, schema)
+	if len(result.Errors) != 3 {
+		t.Errorf("Expected 3 errors from the loaded schema, got %v", result.Errors)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Malformed JSON", data: `{"name": {"type": "string"}`},
+		{name: "Unknown key", data: `{"name": {"type": "string", "requred": true}}`},
+		{name: "Bad regex", data: `{"name": {"type": "string", "regex": "(["}}`},
+		{name: "Bad nested regex", data: `{"a": {"type": "map", "schema": {"b": {"type": "string", "regex": "(["}}}}`},
+		{name: "Trailing data", data: `{} {}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSchema([]byte(tt.data)); err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadSchemaFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(`{"name": {"type": "string"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := LoadSchemaFile(path)
+	if err != nil {
+		t.Fatalf("LoadSchemaFile returned error: %v", err)
+	}
+	if schema["name"].Type != "string" {
+		t.Errorf("unexpected schema: %v", schema)
+	}
+
+	if _, err := LoadSchemaFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
+
+func TestMarshalSchema(t *testing.T) {
+	schema := Schema{
+		"b": {Type: "string", RegexPattern: "^<a&b>$"},
+		"a": {Type: "int", Required: true, Min: 1},
+	}
+
+	got, err := MarshalSchema(schema)
+	if err != nil {
+		t.Fatalf("MarshalSchema returned error: %v", err)
+	}
+
+	want := `{
+  "a": {
+    "type": "int",
+    "required": true,
+    "min": 1
+  },
+  "b": {
+    "type": "string",
+    "regex": "^<a&b>$"
+  }
+}
+`
+	if string(got) != want {
+		t.Errorf("MarshalSchema() =\n%s\nwant\n%s", got, want)
+	}
+
+	roundTrip, err := ParseSchema(got)
+	if err != nil || !strings.Contains(roundTrip["b"].Regex.String(), "<a&b>") {
+		t.Errorf("Expected canonical output to parse back, got %v", err)
+	}
+}
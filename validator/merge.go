@@ -0,0 +1,306 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Extend returns a new schema with the rules of overlay applied on top of
+// base. Neither argument is modified.
+//
+// Fields present in only one of the schemas are copied as they are. For a
+// field present in both, the rules are combined with ExtendRule.
+func Extend(base, overlay Schema) Schema {
+	result := cloneSchema(base)
+	for field, rule := range overlay {
+		if baseRule, ok := result[field]; ok {
+			result[field] = ExtendRule(baseRule, rule)
+		} else {
+			result[field] = cloneRule(rule)
+		}
+	}
+	return result
+}
+
+// Merge combines schemas from left to right with Extend, so later schemas
+// override earlier ones.
+func Merge(schemas ...Schema) Schema {
+	result := Schema{}
+	for _, schema := range schemas {
+		result = Extend(result, schema)
+	}
+	return result
+}
+
+// ExtendRule returns base with the settings of overlay applied on top:
+//
+//   - If overlay sets a different Type, or sets a Ref, it replaces base
+//     entirely, since constraints of another type would no longer apply.
+//   - Otherwise every non-zero field of overlay replaces the base value.
+//     Regex and RegexPattern are replaced together.
+//   - List rules are combined recursively with ExtendRule, nested Schemas
+//     with Extend, and Messages one message at a time.
+//
+// Because only non-zero fields override, Required can be turned on but not
+// off this way; use Optional for that.
+func ExtendRule(base, overlay Rule) Rule {
+	if overlay.Ref != "" || (overlay.Type != "" && overlay.Type != base.Type) {
+		return cloneRule(overlay)
+	}
+
+	result := cloneRule(base)
+	if overlay.Required {
+		result.Required = true
+	}
+	if overlay.Default != nil {
+		result.Default = overlay.Default
+	}
+	if overlay.Min != 0 {
+		result.Min = overlay.Min
+	}
+	if overlay.Max != 0 {
+		result.Max = overlay.Max
+	}
+	if overlay.MinLength != 0 {
+		result.MinLength = overlay.MinLength
+	}
+	if overlay.MaxLength != 0 {
+		result.MaxLength = overlay.MaxLength
+	}
+	if overlay.Regex != nil || overlay.RegexPattern != "" {
+		result.Regex = overlay.Regex
+		result.RegexPattern = overlay.RegexPattern
+	}
+	if overlay.Allowed != nil {
+		result.Allowed = append([]interface{}(nil), overlay.Allowed...)
+	}
+	if overlay.Format != "" {
+		result.Format = overlay.Format
+	}
+	if overlay.Check != "" {
+		result.Check = overlay.Check
+	}
+	if overlay.List != nil {
+		list := cloneRule(*overlay.List)
+		if result.List != nil {
+			list = ExtendRule(*result.List, *overlay.List)
+		}
+		result.List = &list
+	}
+	if overlay.Schema != nil {
+		schema := cloneSchema(*overlay.Schema)
+		if result.Schema != nil {
+			schema = Extend(*result.Schema, *overlay.Schema)
+		}
+		result.Schema = &schema
+	}
+	if overlay.Messages != nil {
+		result.Messages = mergeMessages(result.Messages, overlay.Messages)
+	}
+	if overlay.Title != "" {
+		result.Title = overlay.Title
+	}
+	if overlay.Description != "" {
+		result.Description = overlay.Description
+	}
+	if overlay.Examples != nil {
+		result.Examples = append([]interface{}(nil), overlay.Examples...)
+	}
+	if overlay.Deprecated != "" {
+		result.Deprecated = overlay.Deprecated
+	}
+	if overlay.Severity != SeverityError {
+		result.Severity = overlay.Severity
+	}
+	if overlay.Warn != nil {
+		result.Warn = append([]string(nil), overlay.Warn...)
+	}
+	return result
+}
+
+// Pick returns a copy of schema containing only the named fields. Names not
+// in schema are ignored.
+func Pick(schema Schema, fields ...string) Schema {
+	result := make(Schema, len(fields))
+	for _, field := range fields {
+		if rule, ok := schema[field]; ok {
+			result[field] = cloneRule(rule)
+		}
+	}
+	return result
+}
+
+// Omit returns a copy of schema without the named fields.
+func Omit(schema Schema, fields ...string) Schema {
+	omitted := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		omitted[field] = true
+	}
+
+	result := make(Schema, len(schema))
+	for field, rule := range schema {
+		if !omitted[field] {
+			result[field] = cloneRule(rule)
+		}
+	}
+	return result
+}
+
+// Optional returns a copy of schema with Required turned off for every
+// field, including the fields of nested schemas and list items, which is
+// the usual shape of an update payload. Definitions reached through Ref are
+// shared and therefore left unchanged; use Registry.Optional to relax them
+// as well.
+func Optional(schema Schema) Schema {
+	result := make(Schema, len(schema))
+	for field, rule := range schema {
+		result[field] = optionalRule(rule)
+	}
+	return result
+}
+
+func optionalRule(rule Rule) Rule {
+	rule = cloneRule(rule)
+	rule.Required = false
+	if rule.List != nil {
+		list := optionalRule(*rule.List)
+		rule.List = &list
+	}
+	if rule.Schema != nil {
+		schema := Optional(*rule.Schema)
+		rule.Schema = &schema
+	}
+	return rule
+}
+
+// Optional returns a copy of schema like the package-level Optional, with
+// every Ref replaced by a relaxed copy of the definition it names, so that
+// no field is required at any level. It returns an error for unresolved
+// references and for recursive definitions, which cannot be inlined;
+// validate with ValidateOptions{Partial: true} against such schemas instead.
+func (r *Registry) Optional(schema Schema) (Schema, error) {
+	return r.optionalSchema(schema, "", map[string]bool{})
+}
+
+// optionalSchema relaxes schema, found at prefix, while the definitions in
+// active are being inlined.
+func (r *Registry) optionalSchema(schema Schema, prefix string, active map[string]bool) (Schema, error) {
+	fields := make([]string, 0, len(schema))
+	for field := range schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	result := make(Schema, len(schema))
+	for _, field := range fields {
+		rule, err := r.optionalRule(schema[field], prefix+field, active)
+		if err != nil {
+			return nil, err
+		}
+		result[field] = rule
+	}
+	return result, nil
+}
+
+func (r *Registry) optionalRule(rule Rule, path string, active map[string]bool) (Rule, error) {
+	if name := rule.Ref; name != "" {
+		if active[name] {
+			return Rule{}, fmt.Errorf("%s: cannot inline recursive reference '%s'", path, name)
+		}
+		resolved, err := r.resolve(rule)
+		if err != nil {
+			return Rule{}, fmt.Errorf("%s: %v", path, err)
+		}
+		active[name] = true
+		defer delete(active, name)
+		rule = resolved
+	}
+
+	rule = cloneRule(rule)
+	rule.Required = false
+	if rule.List != nil {
+		list, err := r.optionalRule(*rule.List, path+"[]", active)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.List = &list
+	}
+	if rule.Schema != nil {
+		schema, err := r.optionalSchema(*rule.Schema, path+".", active)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Schema = &schema
+	}
+	return rule, nil
+}
+
+// cloneSchema returns a deep copy of schema.
+func cloneSchema(schema Schema) Schema {
+	result := make(Schema, len(schema))
+	for field, rule := range schema {
+		result[field] = cloneRule(rule)
+	}
+	return result
+}
+
+// cloneRule returns a deep copy of rule. Compiled regexes are immutable and
+// shared.
+func cloneRule(rule Rule) Rule {
+	if rule.Allowed != nil {
+		rule.Allowed = append([]interface{}(nil), rule.Allowed...)
+	}
+	if rule.Examples != nil {
+		rule.Examples = append([]interface{}(nil), rule.Examples...)
+	}
+	if rule.Warn != nil {
+		rule.Warn = append([]string(nil), rule.Warn...)
+	}
+	if rule.List != nil {
+		list := cloneRule(*rule.List)
+		rule.List = &list
+	}
+	if rule.Schema != nil {
+		schema := cloneSchema(*rule.Schema)
+		rule.Schema = &schema
+	}
+	if rule.Messages != nil {
+		messages := *rule.Messages
+		rule.Messages = &messages
+	}
+	return rule
+}
+
+// mergeMessages returns base with the messages set in overlay replacing
+// those of base.
+func mergeMessages(base, overlay *Messages) *Messages {
+	result := Messages{}
+	if base != nil {
+		result = *base
+	}
+	if overlay.Required != nil {
+		result.Required = overlay.Required
+	}
+	if overlay.TypeMismatch != nil {
+		result.TypeMismatch = overlay.TypeMismatch
+	}
+	if overlay.Range != nil {
+		result.Range = overlay.Range
+	}
+	if overlay.Length != nil {
+		result.Length = overlay.Length
+	}
+	if overlay.Pattern != nil {
+		result.Pattern = overlay.Pattern
+	}
+	if overlay.Allowed != nil {
+		result.Allowed = overlay.Allowed
+	}
+	if overlay.Format != nil {
+		result.Format = overlay.Format
+	}
+	if overlay.Check != nil {
+		result.Check = overlay.Check
+	}
+	return &result
+}
@@ -0,0 +1,227 @@
+package validator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func fieldNames(schema Schema) string {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+var auditSchema = Schema{
+	"id":         {Type: "int", Required: true, Min: 1},
+	"created_at": {Type: "string", Required: true},
+	"meta": {Type: "map", Schema: &Schema{
+		"source": {Type: "string", Required: true},
+	}},
+}
+
+func TestExtend(t *testing.T) {
+	overlay := Schema{
+		"name": {Type: "string", Required: true, MaxLength: 50},
+		"id":   {Max: 1000, Messages: &Messages{Range: strPtr("bad id")}},
+		"meta": {Schema: &Schema{
+			"source":  {MaxLength: 20},
+			"version": {Type: "int"},
+		}},
+	}
+
+	got := Extend(auditSchema, overlay)
+
+	if fieldNames(got) != "created_at,id,meta,name" {
+		t.Fatalf("unexpected fields: %s", fieldNames(got))
+	}
+	id := got["id"]
+	if id.Type != "int" || !id.Required || id.Min != 1 || id.Max != 1000 || *id.Messages.Range != "bad id" {
+		t.Errorf("unexpected merged id rule: %+v", id)
+	}
+	meta := *got["meta"].Schema
+	if fieldNames(meta) != "source,version" || meta["source"].MaxLength != 20 || !meta["source"].Required {
+		t.Errorf("unexpected merged meta schema: %+v", meta)
+	}
+
+	if _, ok := (*auditSchema["meta"].Schema)["version"]; ok {
+		t.Errorf("Extend modified the base schema")
+	}
+	if auditSchema["id"].Max != 0 {
+		t.Errorf("Extend modified a base rule")
+	}
+}
+
+func TestExtendRule(t *testing.T) {
+	re := regexp.MustCompile(`^[a-z]+$`)
+	base := Rule{
+		Type:         "string",
+		MinLength:    2,
+		RegexPattern: "^.+$",
+		Messages:     &Messages{Required: strPtr("needed"), Length: strPtr("length")},
+	}
+
+	got := ExtendRule(base, Rule{Regex: re, RegexPattern: re.String(), Messages: &Messages{Length: strPtr("too long")}})
+	if got.Regex != re || got.RegexPattern != "^[a-z]+$" || got.MinLength != 2 {
+		t.Errorf("unexpected regex merge: %+v", got)
+	}
+	if *got.Messages.Required != "needed" || *got.Messages.Length != "too long" {
+		t.Errorf("Expected messages to merge one by one, got %+v", got.Messages)
+	}
+
+	replaced := ExtendRule(base, Rule{Type: "int", Min: 5})
+	if replaced.Type != "int" || replaced.MinLength != 0 || replaced.Messages != nil {
+		t.Errorf("Expected a type change to replace the rule, got %+v", replaced)
+	}
+
+	ref := ExtendRule(base, Rule{Ref: "name"})
+	if ref.Ref != "name" || ref.Type != "" {
+		t.Errorf("Expected a ref to replace the rule, got %+v", ref)
+	}
+
+	list := ExtendRule(Rule{Type: "list", List: &Rule{Type: "string", MinLength: 1}}, Rule{List: &Rule{MaxLength: 9}})
+	if list.List.MinLength != 1 || list.List.MaxLength != 9 {
+		t.Errorf("Expected list items to merge, got %+v", list.List)
+	}
+
+	documented := ExtendRule(Rule{Type: "string", Title: "Name", Description: "Old", Examples: []interface{}{"a"}},
+		Rule{Description: "New", Deprecated: "use full_name"})
+	if documented.Title != "Name" || documented.Description != "New" || len(documented.Examples) != 1 || documented.Deprecated != "use full_name" {
+		t.Errorf("Expected documentation fields to merge, got %+v", documented)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	pagination := Schema{
+		"page":     {Type: "int", Min: 1, Default: 1},
+		"per_page": {Type: "int", Max: 100},
+	}
+	filter := Schema{"q": {Type: "string"}, "per_page": {Max: 50}}
+
+	got := Merge(auditSchema, pagination, filter)
+	if fieldNames(got) != "created_at,id,meta,page,per_page,q" {
+		t.Errorf("unexpected fields: %s", fieldNames(got))
+	}
+	if got["per_page"].Max != 50 {
+		t.Errorf("Expected later schemas to win, got %+v", got["per_page"])
+	}
+	if len(Merge()) != 0 {
+		t.Errorf("Expected empty schema from no arguments")
+	}
+}
+
+func TestPickOmit(t *testing.T) {
+	if got := fieldNames(Pick(auditSchema, "id", "meta", "missing")); got != "id,meta" {
+		t.Errorf("Pick() fields = %s", got)
+	}
+	if got := fieldNames(Omit(auditSchema, "id", "missing")); got != "created_at,meta" {
+		t.Errorf("Omit() fields = %s", got)
+	}
+
+	picked := Pick(auditSchema, "meta")
+	(*picked["meta"].Schema)["extra"] = Rule{Type: "string"}
+	if _, ok := (*auditSchema["meta"].Schema)["extra"]; ok {
+		t.Errorf("Pick shares nested schemas with its input")
+	}
+}
+
+func TestOptional(t *testing.T) {
+	schema := Extend(auditSchema, Schema{
+		"items": {Type: "list", Required: true, List: &Rule{Type: "map", Schema: &Schema{
+			"sku": {Type: "string", Required: true},
+		}}},
+		"owner": {Ref: "user", Required: true},
+	})
+
+	update := Optional(schema)
+
+	result := Validate(map[string]interface{}{
+		"meta":  map[string]interface{}{},
+		"items": []interface{}{map[string]interface{}{}},
+	}, Omit(update, "owner"))
+	if !result.IsValid {
+		t.Errorf("Expected every field to be optional, got errors: %v", result.Errors)
+	}
+	if update["owner"].Required || update["owner"].Ref != "user" {
+		t.Errorf("unexpected owner rule: %+v", update["owner"])
+	}
+	if !(*schema["meta"].Schema)["source"].Required {
+		t.Errorf("Optional modified its input")
+	}
+
+	result = Validate(map[string]interface{}{"id": 0.5}, update)
+	if result.IsValid {
+		t.Errorf("Expected type constraints to still apply")
+	}
+}
+
+func TestRegistryOptional(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterSchema("address", Schema{
+		"zip":  {Type: "string", Required: true},
+		"geo":  {Ref: "point", Required: true},
+		"tags": {Type: "list", List: &Rule{Ref: "tag"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterSchema("point", Schema{"lat": {Type: "float", Required: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterRule("tag", Rule{Type: "string", MinLength: 2}); err != nil {
+		t.Fatal(err)
+	}
+	user := Schema{
+		"name": {Type: "string", Required: true},
+		"home": {Ref: "address", Required: true},
+		"work": {Ref: "address"},
+	}
+
+	update, err := registry.Optional(user)
+	if err != nil {
+		t.Fatalf("Optional returned error: %v", err)
+	}
+	v := New(WithRegistry(registry))
+	result := v.Validate(map[string]interface{}{
+		"home": map[string]interface{}{"geo": map[string]interface{}{}},
+		"work": map[string]interface{}{},
+	}, update)
+	if !result.IsValid {
+		t.Errorf("Expected referenced fields to be optional, got errors: %v", result.Errors)
+	}
+	result = v.Validate(map[string]interface{}{"home": map[string]interface{}{"tags": []interface{}{"x"}}}, update)
+	if result.IsValid {
+		t.Errorf("Expected constraints of referenced rules to still apply")
+	}
+	if user["home"].Schema != nil || user["home"].Ref != "address" || !user["home"].Required {
+		t.Errorf("Optional modified its input")
+	}
+	if address, _ := registry.Schema("address"); !address["zip"].Required {
+		t.Errorf("Optional modified the registered definition")
+	}
+
+	tests := []struct {
+		name   string
+		schema Schema
+		want   string
+	}{
+		{"Unresolved", Schema{"home": {Ref: "missing"}}, "home: unresolved reference 'missing'"},
+		{"Recursive", Schema{"root": {Ref: "comment"}}, "root.replies[]: cannot inline recursive reference 'comment'"},
+	}
+	if err := registry.RegisterSchema("comment", Schema{
+		"text":    {Type: "string", Required: true},
+		"replies": {Type: "list", List: &Rule{Ref: "comment"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := registry.Optional(tt.schema); err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
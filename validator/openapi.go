@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// Component is a named schema published under components/schemas in an
+// OpenAPI document.
+type Component struct {
+	Name        string
+	Schema      Schema
+	Description string
+	// Example, when not nil, is emitted as the component's example payload.
+	Example map[string]interface{}
+}
+
+// OpenAPIComponents renders components as an OpenAPI 3.1 document fragment:
+//
+//	{"components": {"schemas": {"User": {...}, ...}}}
+//
+// OpenAPI 3.1 schema objects are JSON Schema 2020-12, so each component is
+// translated like ExportJSONSchema and the same warnings are returned, with
+// paths prefixed by the component name. Output is deterministic.
+func OpenAPIComponents(components []Component) ([]byte, []Warning, error) {
+	return openAPIComponents(components, nil)
+}
+
+// OpenAPIComponents is like the package-level OpenAPIComponents but resolves
+// Ref rules against the registry. Referenced definitions are emitted as
+// components of their own and referenced with "#/components/schemas/<name>".
+func (r *Registry) OpenAPIComponents(components []Component) ([]byte, []Warning, error) {
+	return openAPIComponents(components, r)
+}
+
+func openAPIComponents(components []Component, registry *Registry) ([]byte, []Warning, error) {
+	e := &jsonSchemaExporter{registry: registry, refPrefix: "#/components/schemas/"}
+	schemas := make(map[string]interface{}, len(components))
+
+	for _, c := range components {
+		if c.Name == "" {
+			return nil, nil, fmt.Errorf("component name must not be empty")
+		}
+		if _, dup := schemas[c.Name]; dup {
+			return nil, nil, fmt.Errorf("duplicate component name %q", c.Name)
+		}
+
+		node := e.object(c.Schema, c.Name+".")
+		if c.Description != "" {
+			node["description"] = c.Description
+		}
+		if c.Example != nil {
+			node["examples"] = []interface{}{c.Example}
+		}
+		schemas[c.Name] = node
+	}
+	for name, def := range e.defs {
+		if _, listed := schemas[name]; !listed {
+			schemas[name] = def
+		}
+	}
+
+	data, err := marshalIndent(map[string]interface{}{
+		"components": map[string]interface{}{"schemas": schemas},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, e.warnings, nil
+}
+
+// GoldenT is the subset of testing.TB used by CheckGolden.
+type GoldenT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// UpdateGoldenEnv is the environment variable that makes CheckGolden rewrite
+// golden files instead of comparing against them.
+const UpdateGoldenEnv = "GO_SCHEMA_UPDATE_GOLDEN"
+
+// CheckGolden fails t when got differs from the checked-in file at path, such
+// as an OpenAPI fragment produced by OpenAPIComponents. Run the tests with
+// GO_SCHEMA_UPDATE_GOLDEN=1 to write got to path after an intended change.
+func CheckGolden(t GoldenT, path string, got []byte) {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("updating golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file %s: %v (run with %s=1 to create it)", path, err, UpdateGoldenEnv)
+		return
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s is out of date (run with %s=1 to update it)\ngot:\n%s\nwant:\n%s", path, UpdateGoldenEnv, got, want)
+	}
+}
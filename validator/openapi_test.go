@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIComponents(t *testing.T) {
+	components := []Component{
+		{
+			Name:        "User",
+			Description: "A registered user",
+			Schema: Schema{
+				"name":  {Type: "string", Required: true, MinLength: 2},
+				"age":   {Type: "int", Min: 18},
+				"roles": {Type: "list", List: &Rule{Type: "string", Allowed: []interface{}{"admin", "user"}}},
+			},
+			Example: map[string]interface{}{"name": "Ann", "age": 30},
+		},
+		{
+			Name: "Address",
+			Schema: Schema{
+				"zip":  {Type: "string", Required: true},
+				"city": {Type: "string", Required: true},
+			},
+		},
+	}
+
+	got, warnings, err := OpenAPIComponents(components)
+	if err != nil {
+		t.Fatalf("OpenAPIComponents returned error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	CheckGolden(t, filepath.Join("testdata", "openapi_components.json"), got)
+}
+
+func TestOpenAPIComponentsErrors(t *testing.T) {
+	if _, _, err := OpenAPIComponents([]Component{{Name: ""}}); err == nil {
+		t.Errorf("Expected error for empty name")
+	}
+	if _, _, err := OpenAPIComponents([]Component{{Name: "A"}, {Name: "A"}}); err == nil {
+		t.Errorf("Expected error for duplicate name")
+	}
+
+	_, warnings, err := OpenAPIComponents([]Component{{Name: "Money", Schema: Schema{"amount": {Type: "decimal"}}}})
+	if err != nil || len(warnings) != 1 || warnings[0].Path != "Money.amount" {
+		t.Errorf("Expected a warning for Money.amount, got %v (%v)", warnings, err)
+	}
+}
+
+// fakeT records failures reported by CheckGolden.
+type fakeT struct {
+	errors []string
+	fatal  bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.fatal = true
+	f.Errorf(format, args...)
+}
+
+func TestCheckGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+
+	ft := &fakeT{}
+	CheckGolden(ft, path, []byte("{}\n"))
+	if !ft.fatal {
+		t.Errorf("Expected a fatal error for a missing golden file")
+	}
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	ft = &fakeT{}
+	CheckGolden(ft, path, []byte("{}\n"))
+	if len(ft.errors) != 0 {
+		t.Fatalf("unexpected errors while updating: %v", ft.errors)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{}\n" {
+		t.Errorf("golden file not written, got %q", data)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "")
+	ft = &fakeT{}
+	CheckGolden(ft, path, []byte("{}\n"))
+	if len(ft.errors) != 0 {
+		t.Errorf("Expected matching golden file to pass, got %v", ft.errors)
+	}
+
+	CheckGolden(ft, path, []byte("[]\n"))
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "out of date") {
+		t.Errorf("Expected mismatch to be reported, got %v", ft.errors)
+	}
+}
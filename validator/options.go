@@ -0,0 +1,145 @@
+package validator
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures a Validator.
+type Option func(*Validator)
+
+// TypeFunc reports whether a value belongs to a custom type.
+type TypeFunc func(value interface{}) bool
+
+// CheckFunc is a custom check referenced by Rule.Check. It runs after every
+// other constraint of the rule has passed, and a non-nil error fails the
+// field with the error's text as message.
+type CheckFunc func(req CheckRequest) error
+
+// CheckRequest is the input of a CheckFunc.
+type CheckRequest struct {
+	Field string                 // Name of the field within its map
+	Value interface{}            // Value of the field
+	Data  map[string]interface{} // The map containing the field, for cross-field checks
+	Now   time.Time              // Time of the validation run, from WithClock
+
+	ctx context.Context
+}
+
+// Context returns the context passed to ValidateContext, or
+// context.Background for the other validation functions.
+func (r CheckRequest) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// WithFailFast reports only the first error, like WithMaxErrors(1).
+func WithFailFast(failFast bool) Option {
+	return func(v *Validator) { v.failFast = failFast }
+}
+
+// WithMaxErrors reports at most n errors, including errors inside nested
+// lists and maps. Validation stops at the next error, which is left out,
+// and sets ValidationResult.Truncated. Zero means no limit.
+func WithMaxErrors(n int) Option {
+	return func(v *Validator) { v.maxErrors = n }
+}
+
+// WithAllowUnknown controls whether fields missing from the schema are
+// accepted, which is the default, or reported with CodeUnknownField.
+func WithAllowUnknown(allow bool) Option {
+	return func(v *Validator) { v.rejectUnknown = !allow }
+}
+
+// WithPartial validates only the fields present in the data, as described
+// in ValidateOptions.Partial.
+func WithPartial(partial bool) Option {
+	return func(v *Validator) { v.partial = partial }
+}
+
+// WithMergePatch applies JSON Merge Patch semantics, as described in
+// ValidateOptions.MergePatch.
+func WithMergePatch(mergePatch bool) Option {
+	return func(v *Validator) { v.mergePatch = mergePatch }
+}
+
+// WithLocale renders error messages from the templates of locale.
+func WithLocale(locale Locale) Option {
+	return func(v *Validator) {
+		v.locale = make(Locale, len(locale))
+		for code, template := range locale {
+			v.locale[code] = template
+		}
+	}
+}
+
+// WithType registers a custom type that rules can name in Type. Built-in
+// type names cannot be redefined. Only Required, Default, Allowed and Check
+// apply to values of a custom type.
+func WithType(name string, fn TypeFunc) Option {
+	return func(v *Validator) { v.types[name] = fn }
+}
+
+// WithFormat registers a string format that rules can name in Format,
+// replacing a built-in format of the same name.
+func WithFormat(name string, fn FormatFunc) Option {
+	return func(v *Validator) { v.formats[name] = fn }
+}
+
+// WithCheck registers a check that rules can name in Check. It replaces an
+// ExternalCheck of the same name.
+func WithCheck(name string, fn CheckFunc) Option {
+	return func(v *Validator) {
+		delete(v.external, name)
+		v.checks[name] = fn
+	}
+}
+
+// WithClock sets the source of CheckRequest.Now. It defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(v *Validator) { v.now = now }
+}
+
+// WithRegistry resolves Ref rules against registry.
+func WithRegistry(registry *Registry) Option {
+	return func(v *Validator) { v.registry = registry }
+}
+
+// WithMaxDepth limits how deeply lists and maps are nested, overriding
+// Registry.MaxDepth and DefaultMaxDepth.
+func WithMaxDepth(depth int) Option {
+	return func(v *Validator) { v.maxDepth = depth }
+}
+
+// WithPromoteWarnings reports warnings, such as deprecated fields and
+// constraints with Rule.Severity SeverityWarning, as errors. It helps to
+// find the data a migration would break before the rules are tightened.
+func WithPromoteWarnings(promote bool) Option {
+	return func(v *Validator) { v.promoteWarnings = promote }
+}
+
+// ValidateOptions changes how ValidateWithOptions checks data.
+type ValidateOptions struct {
+	// Partial validates only the fields present in the data and skips
+	// Required checks at every level of nesting, as suits a PATCH request.
+	// Types and constraints of the fields that are present still apply.
+	// Lists are replaced as a whole by a patch, so their items are still
+	// validated in full.
+	Partial bool
+	// MergePatch applies JSON Merge Patch (RFC 7396) semantics and implies
+	// Partial: a null value removes the field and is accepted unless the
+	// field is Required.
+	MergePatch bool
+}
+
+// ValidateWithOptions is like Validate but honors opts.
+func ValidateWithOptions(data map[string]interface{}, schema Schema, opts ValidateOptions) ValidationResult {
+	return New(WithPartial(opts.Partial), WithMergePatch(opts.MergePatch)).Validate(data, schema)
+}
+
+// ValidateWithOptions is like Registry.Validate but honors opts.
+func (r *Registry) ValidateWithOptions(data map[string]interface{}, schema Schema, opts ValidateOptions) ValidationResult {
+	return New(WithRegistry(r), WithPartial(opts.Partial), WithMergePatch(opts.MergePatch)).Validate(data, schema)
+}
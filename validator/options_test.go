@@ -0,0 +1,457 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var patchSchema = Schema{
+	"name":  {Type: "string", Required: true, MinLength: 2},
+	"email": {Type: "string", Required: true},
+	"bio":   {Type: "string"},
+	"address": {Type: "map", Required: true, Schema: &Schema{
+		"zip":  {Type: "string", Required: true},
+		"city": {Type: "string", Required: true},
+	}},
+	"phones": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+		"number": {Type: "string", Required: true},
+	}}},
+}
+
+func TestPartialValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string]interface{}
+		wantFields []string
+	}{
+		{
+			name: "Only present fields are checked",
+			data: map[string]interface{}{"bio": "hello"},
+		},
+		{
+			name: "Nested required fields are skipped",
+			data: map[string]interface{}{"address": map[string]interface{}{"zip": "12345"}},
+		},
+		{
+			name:       "Constraints still apply",
+			data:       map[string]interface{}{"name": "A", "email": 42},
+			wantFields: []string{"email", "name"},
+		},
+		{
+			name:       "List items are validated in full",
+			data:       map[string]interface{}{"phones": []interface{}{map[string]interface{}{}}},
+			wantFields: []string{"phones[0].number"},
+		},
+		{
+			name:       "Null is a type error",
+			data:       map[string]interface{}{"bio": nil},
+			wantFields: []string{"bio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateWithOptions(tt.data, patchSchema, ValidateOptions{Partial: true})
+			assertErrorFields(t, result, tt.wantFields)
+		})
+	}
+}
+
+func TestMergePatchValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string]interface{}
+		wantFields []string
+	}{
+		{
+			name: "Null removes an optional field",
+			data: map[string]interface{}{"bio": nil},
+		},
+		{
+			name:       "Null cannot remove a required field",
+			data:       map[string]interface{}{"email": nil},
+			wantFields: []string{"email"},
+		},
+		{
+			name:       "Nested objects are patches too",
+			data:       map[string]interface{}{"address": map[string]interface{}{"city": nil, "zip": "1"}},
+			wantFields: []string{"address.city"},
+		},
+		{
+			name:       "Null inside a list is a value",
+			data:       map[string]interface{}{"phones": []interface{}{map[string]interface{}{"number": nil}}},
+			wantFields: []string{"phones[0].number"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateWithOptions(tt.data, patchSchema, ValidateOptions{MergePatch: true})
+			assertErrorFields(t, result, tt.wantFields)
+		})
+	}
+
+	result := ValidateWithOptions(map[string]interface{}{"email": nil}, patchSchema, ValidateOptions{MergePatch: true})
+	if len(result.Errors) == 1 && result.Errors[0].Message != "Field is required and cannot be removed" {
+		t.Errorf("unexpected message: %q", result.Errors[0].Message)
+	}
+}
+
+func TestRegistryValidateWithOptions(t *testing.T) {
+	r := newTestRegistry(t)
+	schema := Schema{"home": {Ref: "address", Required: true}}
+
+	result := r.ValidateWithOptions(map[string]interface{}{"home": map[string]interface{}{"zip": "12345"}}, schema, ValidateOptions{Partial: true})
+	if !result.IsValid {
+		t.Errorf("Expected partial validation through refs, got %v", result.Errors)
+	}
+
+	result = r.ValidateWithOptions(map[string]interface{}{}, schema, ValidateOptions{})
+	if result.IsValid {
+		t.Errorf("Expected required check without Partial")
+	}
+}
+
+// assertErrorFields checks that result has exactly one error per field in
+// want, in any order.
+func assertErrorFields(t *testing.T, result ValidationResult, want []string) {
+	t.Helper()
+	got := map[string]int{}
+	for _, err := range result.Errors {
+		got[err.Field]++
+	}
+	if len(result.Errors) != len(want) || result.IsValid != (len(want) == 0) {
+		t.Errorf("Expected errors for %v, got %v", want, result.Errors)
+		return
+	}
+	for _, field := range want {
+		if got[field] != 1 {
+			t.Errorf("Expected one error for %q, got %v", field, result.Errors)
+		}
+	}
+}
+
+func TestValidatorOptions(t *testing.T) {
+	schema := Schema{
+		"name":  {Type: "string", Required: true, MinLength: 2},
+		"email": {Type: "string", Required: true, Format: "email"},
+		"age":   {Type: "int", Min: 18},
+	}
+	data := map[string]interface{}{"name": "A", "email": "nope", "age": 3, "extra": true}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantCount int
+	}{
+		{name: "Defaults", wantCount: 3},
+		{name: "Fail fast", opts: []Option{WithFailFast(true)}, wantCount: 1},
+		{name: "Max errors", opts: []Option{WithMaxErrors(2)}, wantCount: 2},
+		{name: "Reject unknown fields", opts: []Option{WithAllowUnknown(false)}, wantCount: 4},
+		{name: "Partial", opts: []Option{WithPartial(true)}, wantCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(tt.opts...).Validate(data, schema)
+			if len(result.Errors) != tt.wantCount || result.IsValid {
+				t.Errorf("Expected %d errors, got %v", tt.wantCount, result.Errors)
+			}
+		})
+	}
+
+	result := New(WithAllowUnknown(false)).Validate(map[string]interface{}{"name": "Ann", "email": "a@b.co", "extra": 1}, schema)
+	if len(result.Errors) != 1 || result.Errors[0].Field != "extra" || result.Errors[0].Code != CodeUnknownField {
+		t.Errorf("Expected an unknown field error, got %v", result.Errors)
+	}
+}
+
+func TestValidatorCodesAndLocale(t *testing.T) {
+	schema := Schema{
+		"name":  {Type: "string", Required: true},
+		"age":   {Type: "int", Min: 18},
+		"code":  {Type: "string", MaxLength: 3},
+		"size":  {Type: "int", Allowed: []interface{}{1, 2}},
+		"title": {Type: "string", MinLength: 5, Messages: &Messages{Length: strPtr("custom")}},
+	}
+	data := map[string]interface{}{"age": 12, "code": "abcd", "size": 5, "title": "x"}
+
+	locale := Locale{
+		CodeRequired:  "Campo obligatorio",
+		CodeMin:       "Debe ser al menos {min}, no {value}",
+		CodeMaxLength: "Máximo {max} caracteres",
+		CodeMinLength: "ignored",
+	}
+	want := map[string]ValidationError{
+		"name":  {Field: "name", Code: CodeRequired, Message: "Campo obligatorio"},
+		"age":   {Field: "age", Code: CodeMin, Message: "Debe ser al menos 18, no 12"},
+		"code":  {Field: "code", Code: CodeMaxLength, Message: "Máximo 3 caracteres"},
+		"size":  {Field: "size", Code: CodeAllowed, Message: "Value 5 is not one of the allowed values [1 2]"},
+		"title": {Field: "title", Code: CodeMinLength, Message: "custom"},
+	}
+
+	result := New(WithLocale(locale)).Validate(data, schema)
+	if len(result.Errors) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), result.Errors)
+	}
+	for _, err := range result.Errors {
+		w := want[err.Field]
+		if err.Code != w.Code || err.Message != w.Message {
+			t.Errorf("got %+v, want %+v", err, w)
+		}
+	}
+}
+
+func TestValidatorCustomTypesAndChecks(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	v := New(
+		WithType("timestamp", func(value interface{}) bool {
+			s, ok := value.(string)
+			if !ok {
+				return false
+			}
+			_, err := time.Parse(time.RFC3339, s)
+			return err == nil
+		}),
+		WithCheck("future", func(req CheckRequest) error {
+			ts, _ := time.Parse(time.RFC3339, req.Value.(string))
+			if !ts.After(req.Now) {
+				return fmt.Errorf("%s must be in the future", req.Field)
+			}
+			return nil
+		}),
+		WithCheck("matches_password", func(req CheckRequest) error {
+			if req.Value != req.Data["password"] {
+				return errors.New("Passwords do not match")
+			}
+			return nil
+		}),
+		WithFormat("sku", func(value string) bool { return strings.HasPrefix(value, "SKU-") }),
+		WithClock(func() time.Time { return now }),
+	)
+	schema := Schema{
+		"expires":  {Type: "timestamp", Required: true, Check: "future"},
+		"password": {Type: "string"},
+		"confirm":  {Type: "string", Check: "matches_password"},
+		"sku":      {Type: "string", Format: "sku"},
+	}
+	if err := v.ValidateSchema(schema); err != nil {
+		t.Fatalf("Expected schema to be valid for the validator, got %v", err)
+	}
+	if err := ValidateSchema(schema); err == nil {
+		t.Errorf("Expected the default validator to reject custom types")
+	}
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		wantCode string
+	}{
+		{name: "Valid", data: map[string]interface{}{"expires": "2027-01-01T00:00:00Z", "password": "a", "confirm": "a", "sku": "SKU-1"}},
+		{name: "Custom type mismatch", data: map[string]interface{}{"expires": "tomorrow"}, wantCode: CodeTypeMismatch},
+		{name: "Check uses the clock", data: map[string]interface{}{"expires": "2025-01-01T00:00:00Z"}, wantCode: CodeCheck},
+		{name: "Cross-field check", data: map[string]interface{}{"expires": "2027-01-01T00:00:00Z", "password": "a", "confirm": "b"}, wantCode: CodeCheck},
+		{name: "Custom format", data: map[string]interface{}{"expires": "2027-01-01T00:00:00Z", "sku": "X"}, wantCode: CodeFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.data, schema)
+			if tt.wantCode == "" {
+				if !result.IsValid {
+					t.Errorf("Expected valid data, got %v", result.Errors)
+				}
+				return
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Code != tt.wantCode {
+				t.Errorf("Expected one %s error, got %v", tt.wantCode, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidatorSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema Schema
+	}{
+		{name: "Unknown format", schema: Schema{"a": {Type: "string", Format: "isbn"}}},
+		{name: "Format on non-string", schema: Schema{"a": {Type: "int", Format: "email"}}},
+		{name: "Unknown check", schema: Schema{"a": {Type: "string", Check: "unique"}}},
+		{name: "Unknown type", schema: Schema{"a": {Type: "money"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New().ValidateSchema(tt.schema); err == nil {
+				t.Errorf("Expected an error for %v", tt.schema)
+			}
+		})
+	}
+}
+
+func TestValidatorConcurrentUse(t *testing.T) {
+	v := New(WithMaxErrors(5), WithLocale(Locale{CodeRequired: "missing"}))
+	schema := Schema{"name": {Type: "string", Required: true}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if result := v.Validate(map[string]interface{}{}, schema); result.Errors[0].Message != "missing" {
+					t.Errorf("unexpected result %v", result.Errors)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestValidatorErrorLimits(t *testing.T) {
+	items := make([]interface{}, 100000)
+	for i := range items {
+		items[i] = map[string]interface{}{"qty": -1}
+	}
+	schema := Schema{
+		"a": {Type: "string", MinLength: 5},
+		"orders": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+			"qty": {Type: "int", Min: 1},
+		}}},
+		"z": {Type: "string", Required: true},
+	}
+	data := map[string]interface{}{"orders": items}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantFields []string
+		truncated  bool
+	}{
+		{name: "Fail fast", opts: []Option{WithFailFast(true)}, wantFields: []string{"orders[0].qty"}, truncated: true},
+		{name: "Max errors", opts: []Option{WithMaxErrors(3)}, wantFields: []string{"orders[0].qty", "orders[1].qty", "orders[2].qty"}, truncated: true},
+		{name: "Limit not reached", opts: []Option{WithMaxErrors(5)}, wantFields: []string{"z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := data
+			if !tt.truncated {
+				input = map[string]interface{}{"a": "abcdef"}
+			}
+			result := New(tt.opts...).Validate(input, schema)
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantFields, ",") || result.Truncated != tt.truncated || result.IsValid {
+				t.Errorf("got fields %v, truncated %v; want %v, %v", got, result.Truncated, tt.wantFields, tt.truncated)
+			}
+		})
+	}
+
+	// A field failing several constraints at once still respects the limit
+	multi := Schema{"size": {Type: "string", MinLength: 5, Format: "email", Allowed: []interface{}{"x@y.z"}}}
+	result := New(WithMaxErrors(2)).Validate(map[string]interface{}{"size": "ab"}, multi)
+	if len(result.Errors) != 2 || !result.Truncated {
+		t.Errorf("Expected two errors and truncation, got %v (truncated %v)", result.Errors, result.Truncated)
+	}
+
+	if result := New().Validate(data, schema); len(result.Errors) != len(items)+1 || result.Truncated {
+		t.Errorf("Expected every error without a limit, got %d (truncated %v)", len(result.Errors), result.Truncated)
+	}
+}
+
+type ctxKey string
+
+func TestValidateContext(t *testing.T) {
+	schema := Schema{"name": {Type: "string", Required: true}}
+
+	result, err := ValidateContext(context.Background(), map[string]interface{}{"name": "Ann"}, schema)
+	if err != nil || !result.IsValid || result.Truncated {
+		t.Errorf("Expected a valid result, got %+v, %v", result, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = ValidateContext(ctx, map[string]interface{}{"name": "Ann"}, schema)
+	if !errors.Is(err, context.Canceled) || result.IsValid || !result.Truncated {
+		t.Errorf("Expected a cancelled result, got %+v, %v", result, err)
+	}
+}
+
+func TestValidateContextCancelsTraversal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey("tenant"), "acme"))
+	defer cancel()
+
+	var calls int
+	v := New(WithCheck("slow", func(req CheckRequest) error {
+		calls++
+		if req.Context().Value(ctxKey("tenant")) != "acme" {
+			t.Errorf("Expected the request context in the check")
+		}
+		if calls == 10 {
+			cancel()
+		}
+		return nil
+	}))
+	items := make([]interface{}, 1000)
+	for i := range items {
+		items[i] = "x"
+	}
+	schema := Schema{"ids": {Type: "list", List: &Rule{Type: "string", Check: "slow"}}}
+
+	result, err := v.ValidateContext(ctx, map[string]interface{}{"ids": items}, schema)
+	if !errors.Is(err, context.Canceled) || result.IsValid {
+		t.Errorf("Expected cancellation, got %+v, %v", result, err)
+	}
+	if calls != 10 {
+		t.Errorf("Expected traversal to stop after 10 items, got %d", calls)
+	}
+
+	if got := (CheckRequest{}).Context(); got != context.Background() {
+		t.Errorf("Expected a background context by default")
+	}
+}
+
+func TestTruncatedOnlyWhenSomethingIsLeftOut(t *testing.T) {
+	schema := Schema{
+		"age":  {Type: "int", Min: 18},
+		"name": {Type: "string", Required: true},
+		"tags": {Type: "list", List: &Rule{Type: "string", MinLength: 2}},
+	}
+
+	tests := []struct {
+		name      string
+		data      map[string]interface{}
+		opts      []Option
+		errors    int
+		truncated bool
+	}{
+		{name: "Single error at the limit", data: map[string]interface{}{"age": 1, "name": "Ann"}, opts: []Option{WithMaxErrors(1)}, errors: 1},
+		{name: "Fail fast on the only error", data: map[string]interface{}{"age": 1, "name": "Ann"}, opts: []Option{WithFailFast(true)}, errors: 1},
+		{name: "Last list item at the limit", data: map[string]interface{}{"name": "Ann", "tags": []interface{}{"ok", "x", "y"}}, opts: []Option{WithMaxErrors(2)}, errors: 2},
+		{name: "Missing required field left out", data: map[string]interface{}{"age": 1}, opts: []Option{WithMaxErrors(1)}, errors: 1, truncated: true},
+		{name: "List items left out", data: map[string]interface{}{"name": "Ann", "tags": []interface{}{"x", "y"}}, opts: []Option{WithFailFast(true)}, errors: 1, truncated: true},
+		{name: "Parallel list at the limit", data: map[string]interface{}{"name": "Ann", "tags": []interface{}{"ok", "x", "y"}},
+			opts: []Option{WithMaxErrors(2), WithParallelLists(1, 2)}, errors: 2},
+		{name: "Parallel list items left out", data: map[string]interface{}{"name": "Ann", "tags": []interface{}{"x", "y", "z"}},
+			opts: []Option{WithMaxErrors(2), WithParallelLists(1, 2)}, errors: 2, truncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(tt.opts...).Validate(tt.data, schema)
+			if len(result.Errors) != tt.errors || result.Truncated != tt.truncated {
+				t.Errorf("got %v (truncated %v), want %d errors (truncated %v)", result.Errors, result.Truncated, tt.errors, tt.truncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// WithParallelLists validates the items of lists longer than threshold on
+// a pool of workers goroutines, or runtime.GOMAXPROCS(0) workers if workers
+// is not positive. Errors are merged back in index order, so the result is
+// the same as in sequential mode. Functions registered with WithCheck and
+// WithType must then be safe for concurrent use.
+func WithParallelLists(threshold, workers int) Option {
+	return func(v *Validator) {
+		v.parallelThreshold = threshold
+		v.parallelWorkers = workers
+	}
+}
+
+// itemResult is the outcome of validating one list item on a worker.
+type itemResult struct {
+	errors  []ValidationError
+	pending []pendingCheck
+}
+
+// validateList checks every item of list against rule.
+func (v *validation) validateList(field string, list []interface{}, rule Rule) []ValidationError {
+	if v.parallelThreshold > 0 && len(list) > v.parallelThreshold {
+		return v.validateListParallel(field, list, rule)
+	}
+
+	var validationErrors []ValidationError
+	for i, item := range list {
+		if v.done() {
+			break
+		}
+		mark := len(v.pending)
+		for _, err := range v.validateField("items", item, rule, nil) {
+			err.Path = append(Path{KeySegment(field), IndexSegment(i)}, err.Path[1:]...)
+			validationErrors = append(validationErrors, err)
+		}
+		v.prefixPending(mark, listItemPath(field, i))
+	}
+	return validationErrors
+}
+
+// validateListParallel is validateList on a worker pool. Each worker has its
+// own copy of the run state; the results are then merged in index order,
+// applying the error limit as the sequential loop would have.
+func (v *validation) validateListParallel(field string, list []interface{}, rule Rule) []ValidationError {
+	workers := v.parallelWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]itemResult, len(list))
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := *v
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(list) {
+					return
+				}
+				worker.count, worker.stopped, worker.pending = 0, false, nil
+				if worker.done() {
+					return
+				}
+				results[i] = itemResult{
+					errors:  worker.validateField("items", list[i], rule, nil),
+					pending: worker.pending,
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var validationErrors []ValidationError
+	for i, result := range results {
+		if v.done() {
+			break
+		}
+		mark := len(v.pending)
+		v.pending = append(v.pending, result.pending...)
+		v.prefixPending(mark, listItemPath(field, i))
+		for _, err := range result.errors {
+			err.Path = append(Path{KeySegment(field), IndexSegment(i)}, err.Path[1:]...)
+			validationErrors = append(validationErrors, err)
+			if err.Severity == SeverityWarning {
+				continue
+			}
+			v.count++
+			if v.limit > 0 && v.count > v.limit {
+				v.stopped = true
+				break
+			}
+		}
+	}
+	return validationErrors
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func bigOrder(n int) map[string]interface{} {
+	lines := make([]interface{}, n)
+	for i := range lines {
+		line := map[string]interface{}{"sku": "SKU", "qty": i % 7, "tags": []interface{}{"a", i}}
+		if i%11 == 0 {
+			line["sku"] = 5
+		}
+		lines[i] = line
+	}
+	return map[string]interface{}{"lines": lines}
+}
+
+var bigOrderSchema = Schema{
+	"lines": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+		"sku":  {Type: "string", Required: true, Check: "known_sku"},
+		"qty":  {Type: "int", Min: 1},
+		"tags": {Type: "list", List: &Rule{Type: "string"}},
+	}}},
+}
+
+func TestParallelListsMatchSequential(t *testing.T) {
+	sku := &fakeBatchUsers{fakeUsers: fakeUsers{ids: map[interface{}]bool{"SKU": true}}}
+	data := bigOrder(5000)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "All errors"},
+		{name: "Max errors", opts: []Option{WithMaxErrors(25)}},
+		{name: "Fail fast", opts: []Option{WithFailFast(true)}},
+		{name: "External checks", opts: []Option{WithExternalCheck("known_sku", sku)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithCheck("known_sku", func(CheckRequest) error { return nil })}, tt.opts...)
+			sequential := New(opts...).Validate(data, bigOrderSchema)
+			parallel := New(append(opts, WithParallelLists(100, 4))...).Validate(data, bigOrderSchema)
+
+			if len(sequential.Errors) == 0 {
+				t.Fatalf("Expected errors in the test data")
+			}
+			if !reflect.DeepEqual(sequential, parallel) {
+				t.Errorf("parallel result differs from sequential:\nsequential: %d errors, truncated %v\nparallel:   %d errors, truncated %v",
+					len(sequential.Errors), sequential.Truncated, len(parallel.Errors), parallel.Truncated)
+			}
+		})
+	}
+}
+
+func TestParallelListsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := New(WithParallelLists(10, 2)).ValidateContext(ctx, bigOrder(1000), bigOrderSchema)
+	if !errors.Is(err, context.Canceled) || result.IsValid {
+		t.Errorf("Expected cancellation, got %v, %d errors", err, len(result.Errors))
+	}
+}
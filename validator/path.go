@@ -0,0 +1,271 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PathSegment is one step of a Path: a map key, or a list index if IsIndex
+// is set.
+type PathSegment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// KeySegment returns the segment for a map key.
+func KeySegment(key string) PathSegment {
+	return PathSegment{Key: key}
+}
+
+// IndexSegment returns the segment for a list index.
+func IndexSegment(index int) PathSegment {
+	return PathSegment{Index: index, IsIndex: true}
+}
+
+// Path locates a value inside a document, from the root down.
+type Path []PathSegment
+
+// identifier matches keys that JSONPath can write in dot notation.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// String renders the path in dotted notation, e.g. "orders[2].price", which
+// is what ValidationError.Field holds. Keys that are empty or contain '.',
+// '[', ']' or '"' are written as quoted brackets, e.g. `meta["a.b"]`, so
+// that ParsePath can read every path back.
+func (p Path) String() string {
+	var b strings.Builder
+	for i, seg := range p {
+		switch {
+		case seg.IsIndex:
+			fmt.Fprintf(&b, "[%d]", seg.Index)
+		case seg.Key == "" || strings.ContainsAny(seg.Key, `.[]"`):
+			quoted, _ := json.Marshal(seg.Key)
+			fmt.Fprintf(&b, "[%s]", quoted)
+		default:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(seg.Key)
+		}
+	}
+	return b.String()
+}
+
+// JSONPointer renders the path as an RFC 6901 JSON Pointer, e.g.
+// "/orders/2/price". The empty path is the empty pointer.
+func (p Path) JSONPointer() string {
+	var b strings.Builder
+	for _, seg := range p {
+		b.WriteByte('/')
+		if seg.IsIndex {
+			b.WriteString(strconv.Itoa(seg.Index))
+		} else {
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(seg.Key))
+		}
+	}
+	return b.String()
+}
+
+// JSONPath renders the path as a JSONPath expression, e.g.
+// "$.orders[2].price", quoting keys that are not identifiers: "$['a.b']".
+func (p Path) JSONPath() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, seg := range p {
+		switch {
+		case seg.IsIndex:
+			fmt.Fprintf(&b, "[%d]", seg.Index)
+		case identifier.MatchString(seg.Key):
+			b.WriteByte('.')
+			b.WriteString(seg.Key)
+		default:
+			b.WriteString("['")
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(seg.Key))
+			b.WriteString("']")
+		}
+	}
+	return b.String()
+}
+
+// MarshalJSON encodes the path as an array of keys and indexes, e.g.
+// ["orders", 2, "price"].
+func (p Path) MarshalJSON() ([]byte, error) {
+	items := make([]interface{}, len(p))
+	for i, seg := range p {
+		if seg.IsIndex {
+			items[i] = seg.Index
+		} else {
+			items[i] = seg.Key
+		}
+	}
+	return json.Marshal(items)
+}
+
+// UnmarshalJSON decodes the format written by MarshalJSON.
+func (p *Path) UnmarshalJSON(data []byte) error {
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	path := make(Path, len(items))
+	for i, item := range items {
+		switch item := item.(type) {
+		case string:
+			path[i] = KeySegment(item)
+		case float64:
+			if item != float64(int(item)) || item < 0 {
+				return fmt.Errorf("invalid path index %v", item)
+			}
+			path[i] = IndexSegment(int(item))
+		default:
+			return fmt.Errorf("invalid path segment %v", item)
+		}
+	}
+	*p = path
+	return nil
+}
+
+// ParsePath parses the dotted notation written by Path.String.
+func ParsePath(s string) (Path, error) {
+	path := Path{}
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '[':
+			seg, n, err := parseBracket(s[i:], '"')
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", s, err)
+			}
+			path = append(path, seg)
+			i += n
+		case s[i] == '.' && len(path) > 0:
+			i++
+			fallthrough
+		default:
+			end := i
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+			if end == i || strings.ContainsAny(s[i:end], `]"`) {
+				return nil, fmt.Errorf("invalid path %q: empty or malformed key at offset %d", s, i)
+			}
+			path = append(path, KeySegment(s[i:end]))
+			i = end
+		}
+	}
+	return path, nil
+}
+
+// ParseJSONPointer parses an RFC 6901 JSON Pointer. Pointers do not say
+// whether a token is a key or an index, so tokens that are non-negative
+// integers without leading zeros become indexes.
+func ParseJSONPointer(s string) (Path, error) {
+	path := Path{}
+	if s == "" {
+		return path, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON Pointer %q: must start with '/'", s)
+	}
+	for _, token := range strings.Split(s[1:], "/") {
+		for i := 0; i < len(token); i++ {
+			if token[i] == '~' && (i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1')) {
+				return nil, fmt.Errorf("invalid JSON Pointer %q: bad escape in %q", s, token)
+			}
+		}
+		if index, ok := parseIndex(token); ok {
+			path = append(path, IndexSegment(index))
+			continue
+		}
+		path = append(path, KeySegment(strings.NewReplacer("~1", "/", "~0", "~").Replace(token)))
+	}
+	return path, nil
+}
+
+// ParseJSONPath parses the subset of JSONPath written by Path.JSONPath:
+// "$" followed by ".key", "['key']", "[\"key\"]" and "[index]" steps.
+func ParseJSONPath(s string) (Path, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with '$'", s)
+	}
+	path := Path{}
+	for i := 1; i < len(s); {
+		switch s[i] {
+		case '.':
+			end := i + 1
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+			if !identifier.MatchString(s[i+1 : end]) {
+				return nil, fmt.Errorf("invalid JSONPath %q: bad key at offset %d", s, i+1)
+			}
+			path = append(path, KeySegment(s[i+1:end]))
+			i = end
+		case '[':
+			seg, n, err := parseBracket(s[i:], '\'')
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: %v", s, err)
+			}
+			path = append(path, seg)
+			i += n
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q at offset %d", s, s[i], i)
+		}
+	}
+	return path, nil
+}
+
+// parseBracket parses a "[...]" step at the start of s: an index, or a key
+// quoted with double quotes (JSON escapes) or with quote. It returns the
+// segment and the number of bytes consumed.
+func parseBracket(s string, quote byte) (PathSegment, int, error) {
+	end := strings.IndexByte(s, ']')
+	if len(s) < 3 || end < 0 {
+		return PathSegment{}, 0, fmt.Errorf("unterminated '['")
+	}
+	if s[1] != '"' && s[1] != quote {
+		index, ok := parseIndex(s[1:end])
+		if !ok {
+			return PathSegment{}, 0, fmt.Errorf("invalid index %q", s[1:end])
+		}
+		return IndexSegment(index), end + 1, nil
+	}
+
+	// Find the closing quote, skipping escaped characters
+	q := s[1]
+	i := 2
+	for ; i < len(s) && s[i] != q; i++ {
+		if s[i] == '\\' {
+			i++
+		}
+	}
+	if i+1 >= len(s) || s[i+1] != ']' {
+		return PathSegment{}, 0, fmt.Errorf("unterminated quoted key")
+	}
+	if q == '"' {
+		var key string
+		if err := json.Unmarshal([]byte(s[1:i+1]), &key); err != nil {
+			return PathSegment{}, 0, fmt.Errorf("invalid quoted key %s", s[1:i+1])
+		}
+		return KeySegment(key), i + 2, nil
+	}
+	key := strings.NewReplacer(`\\`, `\`, `\'`, `'`).Replace(s[2:i])
+	return KeySegment(key), i + 2, nil
+}
+
+// parseIndex parses a non-negative decimal integer without leading zeros.
+func parseIndex(s string) (int, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(s)
+	return index, err == nil
+}
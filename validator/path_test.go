@@ -0,0 +1,141 @@
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPathRendering(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        Path
+		dotted      string
+		jsonPointer string
+		jsonPath    string
+	}{
+		{name: "Empty", path: Path{}, dotted: "", jsonPointer: "", jsonPath: "$"},
+		{
+			name:        "Keys and indexes",
+			path:        Path{KeySegment("orders"), IndexSegment(2), KeySegment("price")},
+			dotted:      "orders[2].price",
+			jsonPointer: "/orders/2/price",
+			jsonPath:    "$.orders[2].price",
+		},
+		{
+			name:        "Special characters",
+			path:        Path{KeySegment("a.b"), KeySegment("c/d~e"), KeySegment("it's"), IndexSegment(0)},
+			dotted:      `["a.b"].c/d~e.it's[0]`,
+			jsonPointer: "/a.b/c~1d~0e/it's/0",
+			jsonPath:    `$['a.b']['c/d~e']['it\'s'][0]`,
+		},
+		{
+			name:        "Brackets, quotes and empty keys",
+			path:        Path{KeySegment(`x[0]`), KeySegment(`say "hi"`), KeySegment("")},
+			dotted:      `["x[0]"]["say \"hi\""][""]`,
+			jsonPointer: `/x[0]/say "hi"/`,
+			jsonPath:    `$['x[0]']['say "hi"']['']`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.path.String(); got != tt.dotted {
+				t.Errorf("String() = %q, want %q", got, tt.dotted)
+			}
+			if got := tt.path.JSONPointer(); got != tt.jsonPointer {
+				t.Errorf("JSONPointer() = %q, want %q", got, tt.jsonPointer)
+			}
+			if got := tt.path.JSONPath(); got != tt.jsonPath {
+				t.Errorf("JSONPath() = %q, want %q", got, tt.jsonPath)
+			}
+
+			for name, parse := range map[string]func(string) (Path, error){
+				tt.dotted:   ParsePath,
+				tt.jsonPath: ParseJSONPath,
+			} {
+				parsed, err := parse(name)
+				if err != nil || !reflect.DeepEqual(parsed, tt.path) {
+					t.Errorf("parsing %q = %v, %v; want %v", name, parsed, err, tt.path)
+				}
+			}
+		})
+	}
+}
+
+func TestParseJSONPointer(t *testing.T) {
+	got, err := ParseJSONPointer("/a~1b/0/01/~0x/")
+	want := Path{KeySegment("a/b"), IndexSegment(0), KeySegment("01"), KeySegment("~x"), KeySegment("")}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseJSONPointer() = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (Path, error)
+		input string
+	}{
+		{"Dotted leading dot", ParsePath, ".a"},
+		{"Dotted double dot", ParsePath, "a..b"},
+		{"Dotted bad index", ParsePath, "a[x]"},
+		{"Dotted unterminated", ParsePath, `a["b`},
+		{"Dotted stray bracket", ParsePath, "a]"},
+		{"Pointer without slash", ParseJSONPointer, "a/b"},
+		{"Pointer bad escape", ParseJSONPointer, "/a~2"},
+		{"JSONPath without root", ParseJSONPath, "a.b"},
+		{"JSONPath bad key", ParseJSONPath, "$.1a"},
+		{"JSONPath unterminated", ParseJSONPath, "$['a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if path, err := tt.parse(tt.input); err == nil {
+				t.Errorf("Expected an error for %q, got %v", tt.input, path)
+			}
+		})
+	}
+}
+
+func TestPathJSON(t *testing.T) {
+	path := Path{KeySegment("orders"), IndexSegment(2), KeySegment("price")}
+	data, err := json.Marshal(path)
+	if err != nil || string(data) != `["orders",2,"price"]` {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+
+	var decoded Path
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, path) {
+		t.Errorf("Unmarshal() = %v, %v", decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`["a", 1.5]`), &decoded); err == nil {
+		t.Errorf("Expected an error for a fractional index")
+	}
+}
+
+func TestValidationErrorPaths(t *testing.T) {
+	schema := Schema{
+		"a.b": {Type: "map", Schema: &Schema{
+			"items": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+				"qty": {Type: "int", Min: 1},
+			}}},
+		}},
+	}
+	data := map[string]interface{}{
+		"a.b": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"qty": 1},
+			map[string]interface{}{"qty": 0},
+		}},
+	}
+
+	result := Validate(data, schema)
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected one error, got %v", result.Errors)
+	}
+	err := result.Errors[0]
+	want := Path{KeySegment("a.b"), KeySegment("items"), IndexSegment(1), KeySegment("qty")}
+	if !reflect.DeepEqual(err.Path, want) || err.Field != `["a.b"].items[1].qty` || err.Path.JSONPointer() != "/a.b/items/1/qty" {
+		t.Errorf("unexpected path %v (field %q)", err.Path, err.Field)
+	}
+}
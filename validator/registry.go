@@ -0,0 +1,335 @@
+package validator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry holds schemas and rules registered by name so that rules can refer
+// to them through Rule.Ref instead of copying them into every schema.
+//
+// A Ref naming a registered schema behaves like a "map" rule with that
+// schema; a Ref naming a registered rule behaves like that rule. Required,
+// Default, Messages, Severity, Warn and the documentation fields set on the
+// referencing rule apply on top of the definition, so one definition can
+// back both optional and required fields.
+//
+// Definitions may refer to themselves, directly or through other
+// definitions, to describe tree-shaped data such as comment threads. Set
+// MaxDepth to bound how deeply such data is validated.
+//
+// A Registry is safe for concurrent use once MaxDepth is set.
+type Registry struct {
+	// MaxDepth limits the nesting depth of lists and maps validated with
+	// this registry. Zero means DefaultMaxDepth.
+	MaxDepth int
+
+	mu      sync.RWMutex
+	schemas map[string]Schema
+	rules   map[string]Rule
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas: make(map[string]Schema),
+		rules:   make(map[string]Rule),
+	}
+}
+
+// RegisterSchema registers schema under name. Schemas and rules share one
+// namespace and a name can only be registered once.
+func (r *Registry) RegisterSchema(name string, schema Schema) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkName(name); err != nil {
+		return err
+	}
+	r.schemas[name] = schema
+	return nil
+}
+
+// RegisterRule registers rule under name. See RegisterSchema.
+func (r *Registry) RegisterRule(name string, rule Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkName(name); err != nil {
+		return err
+	}
+	r.rules[name] = rule
+	return nil
+}
+
+// checkName reports invalid or already registered names. r.mu must be held.
+func (r *Registry) checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("registry name must not be empty")
+	}
+	_, isSchema := r.schemas[name]
+	_, isRule := r.rules[name]
+	if isSchema || isRule {
+		return fmt.Errorf("'%s' is already registered", name)
+	}
+	return nil
+}
+
+// Schema returns the schema registered under name.
+func (r *Registry) Schema(name string) (Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schema, ok := r.schemas[name]
+	return schema, ok
+}
+
+// Lookup returns the rule a Ref to name stands for. Registered schemas are
+// returned as "map" rules.
+func (r *Registry) Lookup(name string) (Rule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if rule, ok := r.rules[name]; ok {
+		return rule, true
+	}
+	if schema, ok := r.schemas[name]; ok {
+		return Rule{Type: "map", Schema: &schema}, true
+	}
+	return Rule{}, false
+}
+
+// Names returns the registered names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.schemas)+len(r.rules))
+	for name := range r.schemas {
+		names = append(names, name)
+	}
+	for name := range r.rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadSchema reads a JSON schema from reader and registers it under name.
+func (r *Registry) LoadSchema(name string, reader io.Reader) (Schema, error) {
+	schema, err := LoadSchema(reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.RegisterSchema(name, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// LoadSchemaFile reads a JSON schema file and registers it under the file's
+// base name without extension, so "schemas/address.json" can be referenced
+// as "address". Files may reference each other in any load order; call
+// Check once every file is loaded to report dangling references.
+func (r *Registry) LoadSchemaFile(path string) (Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	schema, err := r.LoadSchema(name, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// Validate is like the package-level Validate but resolves Ref rules
+// against the registry.
+func (r *Registry) Validate(data map[string]interface{}, schema Schema) ValidationResult {
+	return New(WithRegistry(r)).Validate(data, schema)
+}
+
+// ValidateSchema is like the package-level ValidateSchema but resolves Ref
+// rules against the registry and reports the ones that do not resolve.
+func (r *Registry) ValidateSchema(schema Schema) error {
+	return New(WithRegistry(r)).ValidateSchema(schema)
+}
+
+// SchemaProblems is like the package-level SchemaProblems but resolves Ref
+// rules against the registry.
+func (r *Registry) SchemaProblems(schema Schema) []SchemaProblem {
+	return New(WithRegistry(r)).SchemaProblems(schema)
+}
+
+// Check validates every registered schema and rule, in name order.
+func (r *Registry) Check() error {
+	for _, name := range r.Names() {
+		var err error
+		if schema, ok := r.Schema(name); ok {
+			err = r.ValidateSchema(schema)
+		} else {
+			rule, _ := r.Lookup(name)
+			err = r.ValidateSchema(Schema{"rule": rule})
+		}
+		if err != nil {
+			return fmt.Errorf("invalid definition '%s': %v", name, err)
+		}
+	}
+	return nil
+}
+
+// resolve follows rule.Ref, and any refs of the rules it names, to a concrete
+// rule. A nil registry resolves nothing.
+func (r *Registry) resolve(rule Rule) (Rule, error) {
+	if rule.Ref == "" {
+		return rule, nil
+	}
+
+	ref := rule
+	seen := map[string]bool{}
+	for rule.Ref != "" {
+		if seen[rule.Ref] {
+			return Rule{}, fmt.Errorf("reference cycle through '%s'", rule.Ref)
+		}
+		seen[rule.Ref] = true
+
+		var target Rule
+		var ok bool
+		if r != nil {
+			target, ok = r.Lookup(rule.Ref)
+		}
+		if !ok {
+			return Rule{}, fmt.Errorf("unresolved reference '%s'", rule.Ref)
+		}
+		rule = target
+	}
+
+	rule.Required = rule.Required || ref.Required
+	if ref.Default != nil {
+		rule.Default = ref.Default
+	}
+	if ref.Messages != nil {
+		rule.Messages = ref.Messages
+	}
+	if ref.Title != "" {
+		rule.Title = ref.Title
+	}
+	if ref.Description != "" {
+		rule.Description = ref.Description
+	}
+	if ref.Examples != nil {
+		rule.Examples = ref.Examples
+	}
+	if ref.Deprecated != "" {
+		rule.Deprecated = ref.Deprecated
+	}
+	if ref.Severity != SeverityError {
+		rule.Severity = ref.Severity
+	}
+	if ref.Warn != nil {
+		rule.Warn = ref.Warn
+	}
+	return rule, nil
+}
+
+// checkRef validates a rule that has a Ref for ValidateSchema and returns
+// the rule it resolves to.
+func (r *Registry) checkRef(rule Rule) (Rule, error) {
+	resolved, err := r.resolve(rule)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid reference: %v", err)
+	}
+	if cycle := r.requiredCycle(rule.Ref); cycle != nil {
+		return Rule{}, fmt.Errorf("invalid reference: required fields form the cycle %s, so no finite data can satisfy it",
+			strings.Join(cycle, " -> "))
+	}
+	if rule.Type != "" && rule.Type != resolved.Type {
+		return Rule{}, fmt.Errorf("type '%s' does not match referenced type '%s'", rule.Type, resolved.Type)
+	}
+	if rule.Min != 0 || rule.Max != 0 || rule.MinLength != 0 || rule.MaxLength != 0 ||
+		rule.Regex != nil || rule.RegexPattern != "" || rule.Allowed != nil || rule.Format != "" || rule.Check != "" ||
+		rule.List != nil || rule.Schema != nil {
+		return Rule{}, fmt.Errorf("ref cannot be combined with other constraints")
+	}
+	if rule.Default != nil && !matchesType(rule.Default, resolved.Type) {
+		return Rule{}, fmt.Errorf("default value does not match type '%s'", resolved.Type)
+	}
+	return resolved, nil
+}
+
+// requiredCycle returns a cycle of definitions reachable from name in which
+// each definition requires a field referencing the next, or nil if there is
+// none. Recursive definitions are fine as long as the recursion can end,
+// e.g. through an optional field or a list.
+func (r *Registry) requiredCycle(name string) []string {
+	var stack []string
+	onStack := map[string]bool{}
+	done := map[string]bool{}
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		if onStack[name] {
+			for i, n := range stack {
+				if n == name {
+					return append(append([]string(nil), stack[i:]...), name)
+				}
+			}
+		}
+		if done[name] {
+			return nil
+		}
+		rule, ok := r.Lookup(name)
+		if !ok {
+			return nil
+		}
+
+		stack = append(stack, name)
+		onStack[name] = true
+		defer func() {
+			stack = stack[:len(stack)-1]
+			onStack[name] = false
+			done[name] = true
+		}()
+
+		for _, ref := range requiredRefs(rule) {
+			if cycle := visit(ref); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit(name)
+}
+
+// requiredRefs returns the names that any value satisfying rule must
+// contain a value for: rule's own Ref, or the Refs of its required fields.
+func requiredRefs(rule Rule) []string {
+	if rule.Ref != "" {
+		return []string{rule.Ref}
+	}
+	if rule.Type != "map" || rule.Schema == nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(*rule.Schema))
+	for field := range *rule.Schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var refs []string
+	for _, field := range fields {
+		if nested := (*rule.Schema)[field]; nested.Required {
+			refs = append(refs, requiredRefs(nested)...)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,414 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	if err := r.RegisterSchema("address", Schema{
+		"street": {Type: "string", Required: true},
+		"zip":    {Type: "string", Required: true, MinLength: 5},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterRule("email", Rule{Type: "string", MinLength: 3}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if err := r.RegisterSchema("email", Schema{}); err == nil {
+		t.Errorf("Expected error registering a name twice")
+	}
+	if err := r.RegisterRule("", Rule{Type: "string"}); err == nil {
+		t.Errorf("Expected error for empty name")
+	}
+
+	if got := strings.Join(r.Names(), ","); got != "address,email" {
+		t.Errorf("Names() = %q", got)
+	}
+	if _, ok := r.Schema("address"); !ok {
+		t.Errorf("Expected address schema to be registered")
+	}
+	if rule, ok := r.Lookup("address"); !ok || rule.Type != "map" || rule.Schema == nil {
+		t.Errorf("Expected schema lookup to return a map rule, got %+v", rule)
+	}
+	if rule, ok := r.Lookup("email"); !ok || rule.Type != "string" {
+		t.Errorf("unexpected email rule: %+v", rule)
+	}
+	if _, ok := r.Lookup("missing"); ok {
+		t.Errorf("Expected lookup of unknown name to fail")
+	}
+}
+
+func TestRegistryValidate(t *testing.T) {
+	r := newTestRegistry(t)
+	schema := Schema{
+		"home":    {Ref: "address", Required: true},
+		"work":    {Ref: "address"},
+		"contact": {Ref: "email", Messages: &Messages{Length: strPtr("Email too short")}},
+		"history": {Type: "list", List: &Rule{Ref: "address"}},
+	}
+
+	valid := map[string]interface{}{
+		"home":    map[string]interface{}{"street": "Main", "zip": "12345"},
+		"contact": "a@b.c",
+		"history": []interface{}{map[string]interface{}{"street": "Old", "zip": "54321"}},
+	}
+	if result := r.Validate(valid, schema); !result.IsValid {
+		t.Errorf("Expected valid data, got errors: %v", result.Errors)
+	}
+
+	invalid := map[string]interface{}{
+		"work":    map[string]interface{}{"street": "Side", "zip": "1"},
+		"contact": "a",
+		"history": []interface{}{map[string]interface{}{"zip": "54321"}},
+	}
+	result := r.Validate(invalid, schema)
+	got := map[string]string{}
+	for _, err := range result.Errors {
+		got[err.Field] = err.Message
+	}
+	want := map[string]string{
+		"home":              "Field is required",
+		"work.zip":          "String length 1 is less than minimum 5",
+		"contact":           "Email too short",
+		"history[0].street": "Field is required",
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d errors, got %v", len(want), result.Errors)
+	}
+	for field, msg := range want {
+		if got[field] != msg {
+			t.Errorf("%s: got %q, want %q", field, got[field], msg)
+		}
+	}
+}
+
+func TestUnresolvedReference(t *testing.T) {
+	schema := Schema{"home": {Ref: "address"}}
+	data := map[string]interface{}{"home": map[string]interface{}{}}
+
+	result := Validate(data, schema)
+	if result.IsValid || !strings.Contains(result.Errors[0].Message, "unresolved reference 'address'") {
+		t.Errorf("Expected unresolved reference error, got %v", result.Errors)
+	}
+
+	result = NewRegistry().Validate(data, schema)
+	if result.IsValid {
+		t.Errorf("Expected unresolved reference error from an empty registry")
+	}
+}
+
+func TestRegistryValidateSchema(t *testing.T) {
+	r := newTestRegistry(t)
+	if err := r.RegisterRule("alias", Rule{Ref: "email"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterRule("loop", Rule{Ref: "loop"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		schema  Schema
+		wantErr string
+	}{
+		{name: "Resolved ref", schema: Schema{"home": {Ref: "address", Required: true}}},
+		{name: "Ref chain", schema: Schema{"mail": {Ref: "alias"}}},
+		{name: "Matching type", schema: Schema{"home": {Ref: "address", Type: "map"}}},
+		{name: "Nested ref", schema: Schema{"l": {Type: "list", List: &Rule{Ref: "address"}}}},
+		{name: "Dangling ref", schema: Schema{"home": {Ref: "adress"}}, wantErr: "unresolved reference 'adress'"},
+		{name: "Nested dangling ref", schema: Schema{"m": {Type: "map", Schema: &Schema{"x": {Ref: "nope"}}}}, wantErr: "unresolved reference 'nope'"},
+		{name: "Type mismatch", schema: Schema{"home": {Ref: "address", Type: "string"}}, wantErr: "does not match referenced type"},
+		{name: "Extra constraints", schema: Schema{"mail": {Ref: "email", MaxLength: 3}}, wantErr: "cannot be combined"},
+		{name: "Bad default", schema: Schema{"mail": {Ref: "email", Default: 3}}, wantErr: "default value"},
+		{name: "Rule cycle", schema: Schema{"x": {Ref: "loop"}}, wantErr: "reference cycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.ValidateSchema(tt.schema)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid schema, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := ValidateSchema(Schema{"home": {Ref: "address"}}); err == nil {
+		t.Errorf("Expected package-level ValidateSchema to report the dangling ref")
+	}
+	if err := r.Check(); err == nil || !strings.Contains(err.Error(), "'loop'") {
+		t.Errorf("Expected Check to report the loop definition, got %v", err)
+	}
+}
+
+func TestRegistryLoadSchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"user.json":    `{"name": {"type": "string"}, "home": {"ref": "address", "required": true}}`,
+		"address.json": `{"zip": {"type": "string", "regex": "^[0-9]{5}$"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRegistry()
+	user, err := r.LoadSchemaFile(filepath.Join(dir, "user.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Check(); err == nil {
+		t.Errorf("Expected dangling ref before address.json is loaded")
+	}
+	if _, err := r.LoadSchemaFile(filepath.Join(dir, "address.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Check(); err != nil {
+		t.Errorf("Expected refs across files to resolve, got %v", err)
+	}
+
+	result := r.Validate(map[string]interface{}{"home": map[string]interface{}{"zip": "abc"}}, user)
+	if len(result.Errors) != 1 || result.Errors[0].Field != "home.zip" {
+		t.Errorf("Expected regex error from the referenced file, got %v", result.Errors)
+	}
+
+	if _, err := r.LoadSchemaFile(filepath.Join(dir, "user.json")); err == nil {
+		t.Errorf("Expected error loading the same name twice")
+	}
+}
+
+func TestRegistryExportJSONSchema(t *testing.T) {
+	r := newTestRegistry(t)
+	schema := Schema{
+		"home":    {Ref: "address", Required: true},
+		"billing": {Ref: "address"},
+		"contact": {Ref: "email"},
+	}
+
+	data, warnings, err := r.ExportJSONSchema(schema)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("export failed: %v %v", err, warnings)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	props := doc["properties"].(map[string]interface{})
+	if ref := props["home"].(map[string]interface{})["$ref"]; ref != "#/$defs/address" {
+		t.Errorf("unexpected $ref %v", ref)
+	}
+	defs := doc["$defs"].(map[string]interface{})
+	if len(defs) != 2 || defs["address"].(map[string]interface{})["type"] != "object" {
+		t.Errorf("unexpected $defs: %v", defs)
+	}
+
+	_, warnings, _ = ExportJSONSchema(schema)
+	if len(warnings) != 3 {
+		t.Errorf("Expected a warning per unresolvable ref without a registry, got %v", warnings)
+	}
+}
+
+func TestRegistryOpenAPIComponents(t *testing.T) {
+	r := newTestRegistry(t)
+
+	data, warnings, err := r.OpenAPIComponents([]Component{
+		{Name: "User", Schema: Schema{"home": {Ref: "address"}}},
+	})
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("OpenAPIComponents failed: %v %v", err, warnings)
+	}
+	out := string(data)
+	if !strings.Contains(out, `"$ref": "#/components/schemas/address"`) || !strings.Contains(out, `"address": {`) {
+		t.Errorf("Expected the referenced schema as its own component:\n%s", out)
+	}
+}
+
+func newTreeRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	if err := r.RegisterSchema("comment", Schema{
+		"text":    {Type: "string", Required: true},
+		"replies": {Type: "list", List: &Rule{Ref: "comment"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// nestedComments builds a thread in which every comment has one reply.
+func nestedComments(depth int, text string) map[string]interface{} {
+	node := map[string]interface{}{"text": text}
+	for i := 0; i < depth; i++ {
+		node = map[string]interface{}{"text": "reply", "replies": []interface{}{node}}
+	}
+	return node
+}
+
+func TestRecursiveSchema(t *testing.T) {
+	r := newTreeRegistry(t)
+	schema := Schema{"thread": {Ref: "comment", Required: true}}
+
+	if err := r.ValidateSchema(schema); err != nil {
+		t.Fatalf("Expected recursive schema to be valid, got %v", err)
+	}
+	if err := r.Check(); err != nil {
+		t.Fatalf("Expected recursive definition to be valid, got %v", err)
+	}
+
+	result := r.Validate(map[string]interface{}{"thread": nestedComments(5, "root")}, schema)
+	if !result.IsValid {
+		t.Errorf("Expected valid thread, got errors: %v", result.Errors)
+	}
+
+	result = r.Validate(map[string]interface{}{"thread": nestedComments(5, "")}, Schema{
+		"thread": {Ref: "comment"},
+	})
+	if !result.IsValid {
+		t.Errorf("Expected empty text to be valid, got errors: %v", result.Errors)
+	}
+
+	bad := nestedComments(3, "leaf")
+	bad["replies"].([]interface{})[0].(map[string]interface{})["text"] = 42
+	result = r.Validate(map[string]interface{}{"thread": bad}, schema)
+	if result.IsValid {
+		t.Errorf("Expected invalid nested reply to be reported")
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	r := newTreeRegistry(t)
+	schema := Schema{"thread": {Ref: "comment"}}
+
+	result := r.Validate(map[string]interface{}{"thread": nestedComments(DefaultMaxDepth, "deep")}, schema)
+	if result.IsValid || !strings.Contains(result.Errors[0].Message, "Maximum nesting depth 64 exceeded") {
+		t.Errorf("Expected default depth limit to be enforced, got %v", result.Errors)
+	}
+
+	r.MaxDepth = 4
+	result = r.Validate(map[string]interface{}{"thread": nestedComments(1, "ok")}, schema)
+	if !result.IsValid {
+		t.Errorf("Expected shallow thread to be valid, got %v", result.Errors)
+	}
+	result = r.Validate(map[string]interface{}{"thread": nestedComments(2, "too deep")}, schema)
+	if result.IsValid || !strings.Contains(result.Errors[0].Message, "depth 4") {
+		t.Errorf("Expected custom depth limit to be enforced, got %v", result.Errors)
+	}
+}
+
+func TestRequiredReferenceCycle(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterSchema("person", Schema{
+		"name":   {Type: "string"},
+		"parent": {Ref: "person", Required: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterSchema("chicken", Schema{
+		"meta": {Type: "map", Required: true, Schema: &Schema{"egg": {Ref: "egg", Required: true}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterSchema("egg", Schema{"from": {Ref: "chicken", Required: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterSchema("optional", Schema{"next": {Ref: "optional"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.ValidateSchema(Schema{"p": {Ref: "person"}})
+	if err == nil || !strings.Contains(err.Error(), "person -> person") {
+		t.Errorf("Expected self-requiring cycle, got %v", err)
+	}
+	err = r.ValidateSchema(Schema{"c": {Ref: "chicken"}})
+	if err == nil || !strings.Contains(err.Error(), "chicken -> egg -> chicken") {
+		t.Errorf("Expected mutual required cycle, got %v", err)
+	}
+	if err := r.ValidateSchema(Schema{"o": {Ref: "optional", Required: true}}); err != nil {
+		t.Errorf("Expected optional recursion to be valid, got %v", err)
+	}
+}
+
+func TestRecursiveExport(t *testing.T) {
+	r := newTreeRegistry(t)
+
+	data, warnings, err := r.ExportJSONSchema(Schema{"thread": {Ref: "comment"}})
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("export failed: %v %v", err, warnings)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	comment := doc["$defs"].(map[string]interface{})["comment"].(map[string]interface{})
+	replies := comment["properties"].(map[string]interface{})["replies"].(map[string]interface{})
+	if ref := replies["items"].(map[string]interface{})["$ref"]; ref != "#/$defs/comment" {
+		t.Errorf("Expected recursive $ref, got %v", ref)
+	}
+
+	spec, _, err := r.OpenAPIComponents([]Component{{Name: "Thread", Schema: Schema{"root": {Ref: "comment"}}}})
+	if err != nil || !strings.Contains(string(spec), `"$ref": "#/components/schemas/comment"`) {
+		t.Errorf("Expected recursive OpenAPI components, got %v:\n%s", err, spec)
+	}
+}
+
+func TestRegistryExportRequiredDefinition(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterRule("email", Rule{Type: "string", Required: true}); err != nil {
+		t.Fatal(err)
+	}
+	schema := Schema{"e": {Ref: "email"}, "note": {Type: "string"}}
+
+	if result := New(WithRegistry(r)).Validate(map[string]interface{}{}, schema); result.IsValid {
+		t.Fatalf("Expected the required definition to make e required")
+	}
+
+	data, err := r.ToJSONSchema(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if required, _ := doc["required"].([]interface{}); len(required) != 1 || required[0] != "e" {
+		t.Errorf("Expected e to be required in JSON Schema, got %v", doc["required"])
+	}
+
+	spec, _, err := r.OpenAPIComponents([]Component{{Name: "User", Schema: schema}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var components struct {
+		Components struct {
+			Schemas map[string]struct {
+				Required []string `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &components); err != nil {
+		t.Fatal(err)
+	}
+	if required := components.Components.Schemas["User"].Required; len(required) != 1 || required[0] != "e" {
+		t.Errorf("Expected e to be required in the component:\n%s", spec)
+	}
+}
@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is matched by errors.Is for every error returned by
+// ValidationResult.Err.
+var ErrInvalid = errors.New("validation failed")
+
+// ValidationErrors is the error returned by ValidationResult.Err. It
+// unwraps to its ValidationErrors, so errors.As can extract them one at a
+// time, and it matches ErrInvalid.
+type ValidationErrors []ValidationError
+
+// Error lists the errors one per line, like errors.Join.
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the individual errors.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Is reports whether target is ErrInvalid.
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Is lets errors.Is match a ValidationError by its Field and Code; empty
+// fields of target match anything, e.g.
+//
+//	errors.Is(err, validator.ValidationError{Code: validator.CodeRequired})
+func (e ValidationError) Is(target error) bool {
+	t, ok := target.(ValidationError)
+	return ok && (t.Field == "" || t.Field == e.Field) && (t.Code == "" || t.Code == e.Code) &&
+		(t.Message == "" || t.Message == e.Message)
+}
+
+// Err returns nil if the result is valid and its errors as
+// ValidationErrors otherwise.
+func (r ValidationResult) Err() error {
+	if r.IsValid {
+		return nil
+	}
+	return ValidationErrors(r.Errors)
+}
+
+// ErrorsFor returns the errors for the value at path, in dotted notation,
+// and for everything nested inside it. An empty path returns every error.
+func (r ValidationResult) ErrorsFor(path string) []ValidationError {
+	prefix, err := ParsePath(path)
+	if err != nil {
+		return nil
+	}
+	var errs []ValidationError
+	for _, e := range r.Errors {
+		if hasPrefix(e.Path, prefix) {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// HasErrors reports whether ErrorsFor(path) is not empty.
+func (r ValidationResult) HasErrors(path string) bool {
+	return len(r.ErrorsFor(path)) > 0
+}
+
+// FieldErrors groups the error messages by Field.
+func (r ValidationResult) FieldErrors() map[string][]string {
+	fields := make(map[string][]string, len(r.Errors))
+	for _, e := range r.Errors {
+		fields[e.Field] = append(fields[e.Field], e.Message)
+	}
+	return fields
+}
+
+// ErrorNode is a node of the error tree returned by
+// ValidationResult.ErrorTree, which mirrors the shape of the data.
+type ErrorNode struct {
+	Errors []string              `json:"errors,omitempty"` // Messages for the value itself
+	Fields map[string]*ErrorNode `json:"fields,omitempty"` // Errors inside a map, by key
+	Items  map[int]*ErrorNode    `json:"items,omitempty"`  // Errors inside a list, by index
+}
+
+// ErrorTree returns the errors arranged like the data they refer to, e.g.
+// the message for "orders[2].price" is at
+// tree.Fields["orders"].Items[2].Fields["price"].Errors.
+func (r ValidationResult) ErrorTree() *ErrorNode {
+	root := &ErrorNode{}
+	for _, e := range r.Errors {
+		node := root
+		for _, seg := range e.Path {
+			node = node.child(seg)
+		}
+		node.Errors = append(node.Errors, e.Message)
+	}
+	return root
+}
+
+// child returns the node for seg below n, creating it if needed.
+func (n *ErrorNode) child(seg PathSegment) *ErrorNode {
+	if seg.IsIndex {
+		if n.Items == nil {
+			n.Items = map[int]*ErrorNode{}
+		}
+		if n.Items[seg.Index] == nil {
+			n.Items[seg.Index] = &ErrorNode{}
+		}
+		return n.Items[seg.Index]
+	}
+	if n.Fields == nil {
+		n.Fields = map[string]*ErrorNode{}
+	}
+	if n.Fields[seg.Key] == nil {
+		n.Fields[seg.Key] = &ErrorNode{}
+	}
+	return n.Fields[seg.Key]
+}
+
+// hasPrefix reports whether path starts with prefix.
+func hasPrefix(path, prefix Path) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i, seg := range prefix {
+		if path[i] != seg {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func resultFixture() ValidationResult {
+	schema := Schema{
+		"name": {Type: "string", Required: true},
+		"address": {Type: "map", Schema: &Schema{
+			"zip":  {Type: "string", MinLength: 5},
+			"city": {Type: "string", Required: true},
+		}},
+		"orders": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+			"price": {Type: "float", Min: 1},
+		}}},
+	}
+	return Validate(map[string]interface{}{
+		"address": map[string]interface{}{"zip": "1"},
+		"orders":  []interface{}{map[string]interface{}{"price": 2.0}, map[string]interface{}{"price": 0.5}},
+	}, schema)
+}
+
+func TestResultLookups(t *testing.T) {
+	result := resultFixture()
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "address", want: []string{"address.zip", "address.city"}},
+		{path: "address.zip", want: []string{"address.zip"}},
+		{path: "orders[1]", want: []string{"orders[1].price"}},
+		{path: "orders[0]"},
+		{path: "name", want: []string{"name"}},
+		{path: "", want: []string{"address.zip", "address.city", "orders[1].price", "name"}},
+		{path: "bad[path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var got []string
+			for _, err := range result.ErrorsFor(tt.path) {
+				got = append(got, err.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ErrorsFor(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+			if result.HasErrors(tt.path) != (len(tt.want) > 0) {
+				t.Errorf("HasErrors(%q) = %v", tt.path, !(len(tt.want) > 0))
+			}
+		})
+	}
+
+	fields := result.FieldErrors()
+	if len(fields) != 4 || fields["address.zip"][0] != "String length 1 is less than minimum 5" {
+		t.Errorf("unexpected FieldErrors: %v", fields)
+	}
+}
+
+func TestResultErrorTree(t *testing.T) {
+	tree := resultFixture().ErrorTree()
+
+	if got := tree.Fields["orders"].Items[1].Fields["price"].Errors; len(got) != 1 {
+		t.Errorf("Expected one error for orders[1].price, got %v", got)
+	}
+	if got := tree.Fields["name"].Errors; len(got) != 1 || got[0] != "Field is required" {
+		t.Errorf("unexpected name errors: %v", got)
+	}
+
+	data, err := json.Marshal(tree.Fields["address"])
+	want := `{"fields":{"city":{"errors":["Field is required"]},"zip":{"errors":["String length 1 is less than minimum 5"]}}}`
+	if err != nil || string(data) != want {
+		t.Errorf("unexpected JSON %s, %v", data, err)
+	}
+}
+
+func TestResultErr(t *testing.T) {
+	if err := (ValidationResult{IsValid: true}).Err(); err != nil {
+		t.Errorf("Expected nil for a valid result, got %v", err)
+	}
+
+	err := resultFixture().Err()
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected errors.Is(err, ErrInvalid)")
+	}
+	if !errors.Is(err, ValidationError{Code: CodeRequired, Field: "address.city"}) {
+		t.Errorf("Expected to match a required error by code and field")
+	}
+	if errors.Is(err, ValidationError{Code: CodeMaxLength}) {
+		t.Errorf("Did not expect a max_length error")
+	}
+
+	var first ValidationError
+	if !errors.As(err, &first) || first.Field != "address.zip" {
+		t.Errorf("errors.As ValidationError = %v", first)
+	}
+	var all ValidationErrors
+	if !errors.As(err, &all) || len(all) != 4 {
+		t.Errorf("errors.As ValidationErrors = %v", all)
+	}
+
+	joined := errors.Join(io.EOF, err)
+	if !errors.Is(joined, ErrInvalid) || !errors.Is(joined, io.EOF) || !errors.As(joined, &first) {
+		t.Errorf("Expected the error to survive errors.Join")
+	}
+	if err.Error() != "address.zip: String length 1 is less than minimum 5\naddress.city: Field is required\n"+
+		"orders[1].price: Value 0.500000 is less than minimum 1.000000\nname: Field is required" {
+		t.Errorf("unexpected message:\n%s", err)
+	}
+}
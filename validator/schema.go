@@ -10,7 +10,7 @@ type Schema map[string]Rule
 
 // Rule defines validation rules for a single field
 type Rule struct {
-	Type         string         `json:"type"`
+	Type         string         `json:"type,omitempty"`
 	Required     bool           `json:"required,omitempty"`
 	Default      interface{}    `json:"default,omitempty"`
 	Min          float64        `json:"min,omitempty"`
@@ -19,9 +19,26 @@ type Rule struct {
 	MaxLength    int            `json:"max_length,omitempty"`
 	Regex        *regexp.Regexp `json:"-"`
 	RegexPattern string         `json:"regex,omitempty"`
+	Allowed      []interface{}  `json:"allowed,omitempty"`
+	Format       string         `json:"format,omitempty"` // Name of a string format, e.g. "email"
+	Check        string         `json:"check,omitempty"`  // Name of a check registered with WithCheck
 	List         *Rule          `json:"list,omitempty"`
 	Schema       *Schema        `json:"schema,omitempty"`
 	Messages     *Messages      `json:"messages,omitempty"`
+	Ref          string         `json:"ref,omitempty"` // Name of a Registry definition
+
+	// Severity SeverityWarning turns every violation of the rule into a
+	// warning, e.g. for a field that will soon be required. Warn does the
+	// same for single constraints, by code, e.g. a soft limit with
+	// Warn: []string{CodeMax}.
+	Severity Severity `json:"severity,omitempty"`
+	Warn     []string `json:"warn,omitempty"`
+
+	// Documentation, kept in the JSON format and in exported JSON Schema
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Examples    []interface{} `json:"examples,omitempty"`   // Checked by ValidateSchema
+	Deprecated  string        `json:"deprecated,omitempty"` // Reason; data using the field gets a warning
 }
 
 // Messages provides customized error messages
@@ -31,18 +48,32 @@ type Messages struct {
 	Range        *string `json:"range,omitempty"`
 	Length       *string `json:"length,omitempty"`
 	Pattern      *string `json:"pattern,omitempty"`
+	Allowed      *string `json:"allowed,omitempty"`
+	Format       *string `json:"format,omitempty"`
+	Check        *string `json:"check,omitempty"`
 }
 
 // ValidationResult represents the result of validation
 type ValidationResult struct {
 	IsValid bool
 	Errors  []ValidationError
+	// Warnings lists findings that do not make the data invalid, such as
+	// the use of deprecated fields.
+	Warnings []ValidationError
+	// Truncated reports that validation stopped early, because the data has
+	// more errors than the limit set by WithFailFast or WithMaxErrors or
+	// because the context of ValidateContext was done, so errors are left
+	// out.
+	Truncated bool
 }
 
 // ValidationError represents a single validation error
 type ValidationError struct {
-	Field   string
-	Message string
+	Field    string   `json:"field"`          // Path in dotted notation
+	Path     Path     `json:"path,omitempty"` // Location of the value in the data
+	Code     string   `json:"code,omitempty"` // One of the Code constants
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity,omitempty"` // SeverityWarning for ValidationResult.Warnings
 }
 
 // Error returns a string representation of the validation error
@@ -0,0 +1,364 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StreamFormat selects how ValidateStream splits its input into records.
+type StreamFormat int
+
+const (
+	// StreamAuto detects the format from the first non-whitespace byte:
+	// '[' selects StreamArray, anything else StreamNDJSON.
+	StreamAuto StreamFormat = iota
+	// StreamNDJSON reads one JSON object per line.
+	StreamNDJSON
+	// StreamArray reads the elements of a single top-level JSON array.
+	StreamArray
+)
+
+// StreamOptions configures ValidateStream.
+type StreamOptions struct {
+	Format StreamFormat
+	// MaxInvalid stops the stream once this many invalid records have been
+	// seen. Zero means no limit.
+	MaxInvalid int
+	// MaxRecordBytes, if not 0, limits the size of an NDJSON line or array
+	// element. Longer records are reported as invalid without being held in
+	// memory, and reading goes on with the next record.
+	MaxRecordBytes int
+}
+
+// RecordResult is the validation outcome of a single streamed record.
+type RecordResult struct {
+	ValidationResult
+	Index  int   // Zero-based position of the record in the stream
+	Offset int64 // Byte offset where the record starts in the input
+}
+
+// StreamSummary counts the records processed by ValidateStream.
+type StreamSummary struct {
+	Records int
+	Valid   int
+	Invalid int
+	// Stopped reports whether the stream was cut short by MaxInvalid.
+	Stopped bool
+}
+
+// errStopStream is used internally to end a stream once MaxInvalid is hit.
+var errStopStream = errors.New("stream stopped")
+
+// ValidateStream reads NDJSON or a top-level JSON array from r and validates
+// every record against schema without loading the whole input into memory.
+// See Validator.ValidateStream.
+func ValidateStream(r io.Reader, schema Schema, opts StreamOptions, fn func(RecordResult) error) (StreamSummary, error) {
+	return defaultValidator.ValidateStream(context.Background(), r, schema, opts, fn)
+}
+
+// ValidateStream is like the package-level ValidateStream, validating every
+// record with c.ValidateContext. It stops once ctx is done and returns
+// ctx.Err().
+//
+// fn, when not nil, is called once per record in input order. Returning an
+// error from fn stops the stream and that error is returned. A record that is
+// not a JSON object, an NDJSON line that is not valid JSON, or a record over
+// MaxRecordBytes is reported as an invalid record; a syntax error inside a
+// JSON array cannot be recovered from and ends the stream with an error.
+func (c *Validator) ValidateStream(ctx context.Context, r io.Reader, schema Schema, opts StreamOptions, fn func(RecordResult) error) (StreamSummary, error) {
+	var summary StreamSummary
+	br := bufio.NewReader(r)
+
+	emit := func(res RecordResult) error {
+		summary.Records++
+		if res.IsValid {
+			summary.Valid++
+		} else {
+			summary.Invalid++
+		}
+		if fn != nil {
+			if err := fn(res); err != nil {
+				return err
+			}
+		}
+		if opts.MaxInvalid > 0 && summary.Invalid >= opts.MaxInvalid {
+			summary.Stopped = true
+			return errStopStream
+		}
+		return nil
+	}
+
+	format := opts.Format
+	var skipped int64
+	if format == StreamAuto || format == StreamArray {
+		first, n, err := skipSpace(br)
+		if err == io.EOF {
+			return summary, nil
+		}
+		if err != nil {
+			return summary, err
+		}
+		skipped = n
+		if format == StreamAuto {
+			format = StreamNDJSON
+			if first == '[' {
+				format = StreamArray
+			}
+		}
+	}
+
+	validate := func(raw []byte) (ValidationResult, error) {
+		return c.validateRecord(ctx, raw, schema)
+	}
+	var err error
+	if format == StreamArray {
+		err = streamArray(br, skipped, opts.MaxRecordBytes, validate, emit)
+	} else {
+		err = streamNDJSON(br, skipped, opts.MaxRecordBytes, validate, emit)
+	}
+	if err == errStopStream {
+		err = nil
+	}
+	return summary, err
+}
+
+// skipSpace consumes leading JSON whitespace and returns the next byte
+// without consuming it, along with the number of bytes skipped.
+func skipSpace(br *bufio.Reader) (byte, int64, error) {
+	var n int64
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, n, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			n++
+		default:
+			return b, n, br.UnreadByte()
+		}
+	}
+}
+
+// recordValidator validates one raw record.
+type recordValidator func(raw []byte) (ValidationResult, error)
+
+func streamNDJSON(br *bufio.Reader, offset int64, max int, validate recordValidator, emit func(RecordResult) error) error {
+	index := 0
+	for {
+		line, n, tooLarge, readErr := readLine(br, max)
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		start := offset
+		offset += n
+
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) > 0 || tooLarge {
+			start += int64(len(line) - len(bytes.TrimLeft(line, " \t\r\n")))
+			res := RecordResult{Index: index, Offset: start}
+			var err error
+			if tooLarge {
+				res.ValidationResult = recordTooLarge(max)
+			} else if res.ValidationResult, err = validate(trimmed); err != nil {
+				return err
+			}
+			index++
+			if err := emit(res); err != nil {
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// readLine reads up to and including the next newline and returns the line,
+// the number of bytes read, and whether the line without its line ending is
+// over max bytes. Only the first max+2 bytes of such a line are kept.
+func readLine(br *bufio.Reader, max int) ([]byte, int64, bool, error) {
+	var line []byte
+	var n int64
+	for {
+		chunk, err := br.ReadSlice('\n')
+		n += int64(len(chunk))
+		if max == 0 {
+			line = append(line, chunk...)
+		} else if keep := max + 2 - len(line); keep > 0 {
+			if keep > len(chunk) {
+				keep = len(chunk)
+			}
+			line = append(line, chunk[:keep]...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		length := n
+		if bytes.HasSuffix(chunk, []byte("\r\n")) {
+			length -= 2
+		} else if bytes.HasSuffix(chunk, []byte("\n")) {
+			length--
+		}
+		tooLarge := max > 0 && length > int64(max)
+		if !tooLarge {
+			line = bytes.TrimRight(line, "\r\n")
+		}
+		return line, n, tooLarge, err
+	}
+}
+
+func streamArray(br *bufio.Reader, offset int64, max int, validate recordValidator, emit func(RecordResult) error) error {
+	b, err := br.ReadByte()
+	if err != nil {
+		return err
+	}
+	if b != '[' {
+		return fmt.Errorf("expected a JSON array, got %q", b)
+	}
+	offset++
+
+	for index := 0; ; index++ {
+		next, n, err := skipSpace(br)
+		offset += n
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if next == ']' && index == 0 {
+			br.ReadByte()
+			break
+		}
+
+		raw, n, tooLarge, err := readElement(br, max)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", index, unexpectedEOF(err))
+		}
+		res := RecordResult{Index: index, Offset: offset}
+		offset += n
+		if tooLarge {
+			res.ValidationResult = recordTooLarge(max)
+		} else {
+			if !json.Valid(raw) {
+				var v interface{}
+				return fmt.Errorf("record %d: %w", index, json.Unmarshal(raw, &v))
+			}
+			if res.ValidationResult, err = validate(raw); err != nil {
+				return err
+			}
+		}
+		if err := emit(res); err != nil {
+			return err
+		}
+
+		_, n, err = skipSpace(br)
+		offset += n
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		b, _ := br.ReadByte()
+		offset++
+		if b == ']' {
+			break
+		}
+		if b != ',' {
+			return fmt.Errorf("invalid character %q after array element", b)
+		}
+	}
+
+	if _, _, err := skipSpace(br); err != io.EOF {
+		if err != nil {
+			return err
+		}
+		return errors.New("unexpected data after JSON array")
+	}
+	return nil
+}
+
+// readElement reads one JSON value of an array and returns it, the number of
+// bytes read, and whether it is over max bytes, in which case only its
+// first max bytes are kept. The value is not checked beyond finding its end.
+func readElement(br *bufio.Reader, max int) ([]byte, int64, bool, error) {
+	var raw []byte
+	var n int64
+	depth, inString, escaped := 0, false, false
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, n, false, err
+		}
+		if !inString && depth == 0 && n > 0 && raw[0] != '"' {
+			// Numbers and literals end before the next delimiter
+			if b == ',' || b == ']' || b == ' ' || b == '\t' || b == '\r' || b == '\n' {
+				return raw, n, max > 0 && n > int64(max), br.UnreadByte()
+			}
+		}
+		n++
+		if max == 0 || len(raw) < max {
+			raw = append(raw, b)
+		}
+
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == '{' || b == '[':
+			depth++
+		case b == '}' || b == ']':
+			depth--
+		}
+		if depth <= 0 && !inString && (b == '}' || b == ']' || (b == '"' && n > 1)) {
+			return raw, n, max > 0 && n > int64(max), nil
+		}
+	}
+}
+
+// unexpectedEOF turns io.EOF in the middle of an array into
+// io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// recordTooLarge is the result for a record over max bytes.
+func recordTooLarge(max int) ValidationResult {
+	return ValidationResult{
+		IsValid: false,
+		Errors:  []ValidationError{{Field: "", Message: fmt.Sprintf("Record exceeds %d bytes", max)}},
+	}
+}
+
+// validateRecord decodes a single JSON object and validates it.
+func (c *Validator) validateRecord(ctx context.Context, raw []byte, schema Schema) (ValidationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ValidationResult{}, err
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(raw, &record); err != nil || record == nil {
+		msg := "Record is not a JSON object"
+		if err != nil {
+			msg = fmt.Sprintf("Invalid record: %v", err)
+		}
+		return ValidationResult{
+			IsValid: false,
+			Errors:  []ValidationError{{Field: "", Message: msg}},
+		}, nil
+	}
+	return c.ValidateContext(ctx, record, schema)
+}
@@ -0,0 +1,200 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var streamSchema = Schema{
+	"id":   {Type: "int", Required: true},
+	"name": {Type: "string", MinLength: 2},
+}
+
+func collectStream(t *testing.T, input string, opts StreamOptions) ([]RecordResult, StreamSummary) {
+	t.Helper()
+	var results []RecordResult
+	summary, err := ValidateStream(strings.NewReader(input), streamSchema, opts, func(r RecordResult) error {
+		results = append(results, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ValidateStream returned error: %v", err)
+	}
+	return results, summary
+}
+
+func TestValidateStreamNDJSON(t *testing.T) {
+	input := "{\"id\": 1, \"name\": \"ok\"}\n\n  {\"name\": \"x\"}\nnot json\n[1]\n{\"id\": 2}"
+
+	results, summary := collectStream(t, input, StreamOptions{})
+
+	if summary.Records != 5 || summary.Valid != 2 || summary.Invalid != 3 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	wantOffsets := []int64{0, 27, 41, 50, 54}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("record %d: Index = %d", i, r.Index)
+		}
+		if r.Offset != wantOffsets[i] {
+			t.Errorf("record %d: Offset = %d, want %d", i, r.Offset, wantOffsets[i])
+		}
+	}
+
+	if len(results[1].Errors) != 2 {
+		t.Errorf("Expected 2 errors for record 1, got %v", results[1].Errors)
+	}
+	if results[2].IsValid || results[3].IsValid {
+		t.Errorf("Expected malformed and non-object records to be invalid")
+	}
+}
+
+func TestValidateStreamArray(t *testing.T) {
+	input := ` [ {"id": 1}, {"id": "two"},
+	{"id": 3, "name": "abc"} ]`
+
+	results, summary := collectStream(t, input, StreamOptions{})
+
+	if summary.Records != 3 || summary.Invalid != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	for _, r := range results {
+		if input[r.Offset] != '{' {
+			t.Errorf("record %d: Offset %d does not point at the record", r.Index, r.Offset)
+		}
+	}
+	if results[1].IsValid {
+		t.Errorf("Expected record 1 to be invalid")
+	}
+}
+
+func TestValidateStreamFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  StreamFormat
+		records int
+		wantErr bool
+	}{
+		{name: "Empty input", input: "  \n", records: 0},
+		{name: "Empty array", input: "[]", records: 0},
+		{name: "Forced array", input: `[{"id": 1}]`, format: StreamArray, records: 1},
+		{name: "Array syntax error", input: `[{"id": 1}, {"id": }]`, records: 1, wantErr: true},
+		{name: "Trailing data after array", input: `[{"id": 1}] {}`, records: 1, wantErr: true},
+		{name: "Forced array on object", input: `{"id": 1}`, format: StreamArray, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := ValidateStream(strings.NewReader(tt.input), streamSchema, StreamOptions{Format: tt.format}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if summary.Records != tt.records {
+				t.Errorf("Records = %d, want %d", summary.Records, tt.records)
+			}
+		})
+	}
+}
+
+func TestValidateStreamMaxInvalid(t *testing.T) {
+	input := strings.Repeat("{\"id\": \"bad\"}\n", 10)
+
+	results, summary := collectStream(t, input, StreamOptions{MaxInvalid: 3})
+
+	if !summary.Stopped {
+		t.Errorf("Expected stream to report Stopped")
+	}
+	if len(results) != 3 || summary.Invalid != 3 {
+		t.Errorf("Expected to stop after 3 invalid records, got %d (%+v)", len(results), summary)
+	}
+}
+
+func TestValidateStreamCallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	input := "{\"id\": 1}\n{\"id\": 2}\n"
+
+	summary, err := ValidateStream(strings.NewReader(input), streamSchema, StreamOptions{}, func(RecordResult) error {
+		return stop
+	})
+
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+	if summary.Records != 1 {
+		t.Errorf("Expected stream to stop after the first record, got %d", summary.Records)
+	}
+}
+
+func TestValidateStreamMaxRecordBytes(t *testing.T) {
+	long := `{"id": 2, "name": "` + strings.Repeat("x", 10000) + `"}`
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "NDJSON", input: "{\"id\": 1}\n" + long + "\r\n  {\"id\": 3, \"name\": \"abcdefghij\"}\n"},
+		{name: "Array", input: `[{"id": 1}, ` + long + ` , {"id": 3, "name": "abcdefghij"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, summary := collectStream(t, tt.input, StreamOptions{MaxRecordBytes: 40})
+			if summary.Records != 3 || summary.Valid != 2 || summary.Invalid != 1 {
+				t.Fatalf("unexpected summary: %+v", summary)
+			}
+			if results[1].IsValid || results[1].Errors[0].Message != "Record exceeds 40 bytes" {
+				t.Errorf("Expected the long record to be rejected, got %+v", results[1])
+			}
+			for _, r := range results {
+				if tt.input[r.Offset] != '{' {
+					t.Errorf("record %d: Offset %d does not point at the record", r.Index, r.Offset)
+				}
+			}
+			if !results[2].IsValid {
+				t.Errorf("Expected reading to go on after the long record, got %v", results[2].Errors)
+			}
+		})
+	}
+}
+
+func TestValidateStreamArrayElements(t *testing.T) {
+	input := `[{"id": 1, "name": "a]\"}"}, 5, "x,y", [1, {}], null, {"id": 2}]`
+
+	results, summary := collectStream(t, input, StreamOptions{MaxRecordBytes: 64})
+
+	if summary.Records != 6 || summary.Valid != 2 || summary.Invalid != 4 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if !results[0].IsValid || !results[5].IsValid {
+		t.Errorf("Expected the objects to be valid, got %v and %v", results[0].Errors, results[5].Errors)
+	}
+	for _, r := range results {
+		if input[r.Offset] == ' ' || input[r.Offset] == ',' {
+			t.Errorf("record %d: Offset %d does not point at the record", r.Index, r.Offset)
+		}
+	}
+}
+
+func TestValidatorValidateStream(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterRule("id", Rule{Type: "int", Required: true, Min: 1}); err != nil {
+		t.Fatal(err)
+	}
+	v := New(WithRegistry(registry))
+	schema := Schema{"id": {Ref: "id"}}
+
+	summary, err := v.ValidateStream(context.Background(), strings.NewReader("{\"id\": 1}\n{\"id\": 0}\n{}\n"), schema, StreamOptions{}, nil)
+	if err != nil || summary.Valid != 1 || summary.Invalid != 2 {
+		t.Errorf("unexpected summary %+v, err %v", summary, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	summary, err = v.ValidateStream(ctx, strings.NewReader(`[{"id": 1}]`), schema, StreamOptions{}, nil)
+	if err != context.Canceled || summary.Records != 0 {
+		t.Errorf("Expected context.Canceled before any record, got %+v, %v", summary, err)
+	}
+}
@@ -1,5 +1,7 @@
 package validator
 
+import "reflect"
+
 // Add these functions to your validator.go file
 
 // extractIntValue extracts an int64 value from different numeric types
@@ -78,3 +80,21 @@ func isValidJSONKey(key string) bool {
 	}
 	return true
 }
+
+// isAllowed returns true if value equals one of the allowed values. Numbers
+// are compared by value so that 1, int64(1) and float64(1) are equal.
+func isAllowed(value interface{}, allowed []interface{}) bool {
+	num, isNum := extractFloatValue(value)
+	for _, candidate := range allowed {
+		if isNum {
+			if c, ok := extractFloatValue(candidate); ok && c == num {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
@@ -282,3 +282,29 @@ func TestIsValidJSONKey(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		allowed []interface{}
+		want    bool
+	}{
+		{name: "String match", value: "red", allowed: []interface{}{"red", "green"}, want: true},
+		{name: "String mismatch", value: "blue", allowed: []interface{}{"red", "green"}, want: false},
+		{name: "Int matches float64", value: 2, allowed: []interface{}{float64(1), float64(2)}, want: true},
+		{name: "Float64 matches int", value: float64(3), allowed: []interface{}{3}, want: true},
+		{name: "Number does not match string", value: 1, allowed: []interface{}{"1"}, want: false},
+		{name: "Bool match", value: false, allowed: []interface{}{false}, want: true},
+		{name: "List match", value: []interface{}{"a"}, allowed: []interface{}{[]interface{}{"a"}}, want: true},
+		{name: "Empty allowed", value: "x", allowed: []interface{}{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAllowed(tt.value, tt.allowed); got != tt.want {
+				t.Errorf("isAllowed(%v, %v) = %v, want %v", tt.value, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 )
 
 /*
@@ -10,6 +14,9 @@ This function is a fundamental part of schemas.
 Its purpose is to check if a provided value matches an expected type.
 */
 func matchesType(value interface{}, expectedType string) bool {
+	if value == nil {
+		return false // JSON null never matches a concrete type
+	}
 	t := reflect.TypeOf(value)
 
 	switch expectedType {
@@ -38,90 +45,294 @@ func matchesType(value interface{}, expectedType string) bool {
 }
 
 // validateNumeric checks if a numeric value conforms to the specified rules.
-func validateNumeric(value interface{}, rule Rule) (bool, string) {
+func validateNumeric(value interface{}, rule Rule) (bool, violation) {
 	if rule.Type == "int" {
 		intVal, ok := extractIntValue(value)
 		if !ok {
-			return false, fmt.Sprintf("Failed to convert %v to integer", value)
+			return false, violation{CodeTypeMismatch, fmt.Sprintf("Failed to convert %v to integer", value),
+				map[string]interface{}{"type": rule.Type, "value": value}}
 		}
 		if rule.Min != 0 && intVal < int64(rule.Min) {
-			return false, fmt.Sprintf("Value %d is less than minimum %d", intVal, int64(rule.Min))
+			return false, violation{CodeMin, fmt.Sprintf("Value %d is less than minimum %d", intVal, int64(rule.Min)),
+				map[string]interface{}{"min": int64(rule.Min), "value": intVal}}
 		}
 		if rule.Max != 0 && intVal > int64(rule.Max) {
-			return false, fmt.Sprintf("Value %d is greater than maximum %d", intVal, int64(rule.Max))
+			return false, violation{CodeMax, fmt.Sprintf("Value %d is greater than maximum %d", intVal, int64(rule.Max)),
+				map[string]interface{}{"max": int64(rule.Max), "value": intVal}}
 		}
 	} else if rule.Type == "float" {
 		floatVal, ok := extractFloatValue(value)
 		if !ok {
-			return false, fmt.Sprintf("Failed to convert %v to float", value)
+			return false, violation{CodeTypeMismatch, fmt.Sprintf("Failed to convert %v to float", value),
+				map[string]interface{}{"type": rule.Type, "value": value}}
 		}
 		if rule.Min != 0 && floatVal < float64(rule.Min) {
-			return false, fmt.Sprintf("Value %f is less than minimum %f", floatVal, rule.Min)
+			return false, violation{CodeMin, fmt.Sprintf("Value %f is less than minimum %f", floatVal, rule.Min),
+				map[string]interface{}{"min": rule.Min, "value": floatVal}}
 		}
 		if rule.Max != 0 && floatVal > float64(rule.Max) {
-			return false, fmt.Sprintf("Value %f is greater than maximum %f", floatVal, rule.Max)
+			return false, violation{CodeMax, fmt.Sprintf("Value %f is greater than maximum %f", floatVal, rule.Max),
+				map[string]interface{}{"max": rule.Max, "value": floatVal}}
 		}
 	}
-	return true, ""
+	return true, violation{}
 }
 
 // validateString checks if a string value conforms to the specified rules.
-func validateString(value string, rule Rule) (bool, string) {
+func validateString(value string, rule Rule) (bool, violation) {
 	if rule.MinLength != 0 && len(value) < rule.MinLength {
-		return false, fmt.Sprintf("String length %d is less than minimum %d", len(value), rule.MinLength)
+		return false, violation{CodeMinLength, fmt.Sprintf("String length %d is less than minimum %d", len(value), rule.MinLength),
+			map[string]interface{}{"min": rule.MinLength, "length": len(value)}}
 	}
 	if rule.MaxLength != 0 && len(value) > rule.MaxLength {
-		return false, fmt.Sprintf("String length %d is greater than maximum %d", len(value), rule.MaxLength)
+		return false, violation{CodeMaxLength, fmt.Sprintf("String length %d is greater than maximum %d", len(value), rule.MaxLength),
+			map[string]interface{}{"max": rule.MaxLength, "length": len(value)}}
 	}
 	if rule.Regex != nil && !rule.Regex.MatchString(value) {
-		return false, "String does not match pattern"
+		return false, violation{CodePattern, "String does not match pattern",
+			map[string]interface{}{"pattern": rule.Regex.String(), "value": value}}
 	}
-	return true, ""
+	return true, violation{}
+}
+
+// builtinTypes are the types matchesType understands.
+var builtinTypes = map[string]bool{
+	"string": true, "int": true, "float": true,
+	"bool": true, "list": true, "map": true,
 }
 
-// ValidateSchema checks if the provided schema is valid.
+// ValidateSchema checks if the provided schema is valid. If it is not, the
+// error is a *SchemaError listing every problem found, with its path.
+//
+// Rules with a Ref cannot be resolved without a Registry and are reported as
+// dangling references; use Registry.ValidateSchema for schemas that use refs.
+// Likewise, custom types and checks are only known to the Validator they
+// were registered with; use Validator.ValidateSchema for those.
 func ValidateSchema(schema Schema) error {
-	validTypes := map[string]bool{
-		"string": true, "int": true, "float": true,
-		"bool": true, "list": true, "map": true,
+	return defaultValidator.ValidateSchema(schema)
+}
+
+// SchemaProblems returns every problem of schema, warnings included, in
+// path order. Warnings point out constructs that are legal but probably
+// not what was meant, such as a custom message that can never be shown.
+func SchemaProblems(schema Schema) []SchemaProblem {
+	return defaultValidator.SchemaProblems(schema)
+}
+
+// ValidateSchema checks if the provided schema is valid for this Validator,
+// taking its registry, custom types, formats and checks into account. It
+// returns a *SchemaError if any problem has SeverityError; the error then
+// lists the warnings too.
+func (c *Validator) ValidateSchema(schema Schema) error {
+	problems := c.SchemaProblems(schema)
+	for _, problem := range problems {
+		if problem.Severity == SeverityError {
+			return &SchemaError{Problems: problems}
+		}
 	}
+	return nil
+}
 
-	for field, rule := range schema {
+// SchemaProblems is like the package-level SchemaProblems, for this
+// Validator.
+func (c *Validator) SchemaProblems(schema Schema) []SchemaProblem {
+	sc := &schemaChecker{Validator: c}
+	sc.schema(schema, "")
+	return sc.problems
+}
+
+// schemaChecker collects the problems of a schema. Paths name fields by key,
+// the item rule of a list as "items" and the fields of a nested schema
+// under "schema", e.g. "orders.items.schema.price".
+type schemaChecker struct {
+	*Validator
+	problems []SchemaProblem
+}
+
+func (sc *schemaChecker) report(path string, severity Severity, format string, args ...interface{}) {
+	sc.problems = append(sc.problems, SchemaProblem{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+func (sc *schemaChecker) schema(schema Schema, prefix string) {
+	fields := make([]string, 0, len(schema))
+	for field := range schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
 		// 1. Validar nombre del campo
 		if !isValidJSONKey(field) {
-			return fmt.Errorf("invalid field name: '%s'", field)
+			sc.report(prefix+field, SeverityError, "invalid field name: '%s'", field)
 		}
+		sc.rule(schema[field], prefix+field)
+	}
+}
 
-		// 2. Validar tipo
-		if _, ok := validTypes[rule.Type]; !ok {
-			return fmt.Errorf("invalid type '%s' for field '%s'", rule.Type, field)
+func (sc *schemaChecker) rule(rule Rule, path string) {
+	// Las referencias se validan contra su definición
+	if rule.Ref != "" {
+		resolved, err := sc.registry.checkRef(rule)
+		if err != nil {
+			sc.report(path, SeverityError, "%v", err)
+			return
 		}
+		sc.severity(resolved, path)
+		sc.messages(resolved, path)
+		sc.examples(resolved, path)
+		return
+	}
 
-		// 3. Validar valores por defecto
-		if rule.Default != nil && !matchesType(rule.Default, rule.Type) {
-			return fmt.Errorf("default value for '%s' does not match type '%s'", field, rule.Type)
+	// 2. Validar tipo
+	if !builtinTypes[rule.Type] && sc.types[rule.Type] == nil {
+		sc.report(path, SeverityError, "invalid type '%s'", rule.Type)
+		return
+	}
+
+	// 3. Validar valores por defecto
+	if rule.Default != nil && !sc.matchesType(rule.Default, rule.Type) {
+		sc.report(path, SeverityError, "default value does not match type '%s'", rule.Type)
+	}
+
+	// 4. Validar valores permitidos
+	for _, allowed := range rule.Allowed {
+		if !sc.matchesType(allowed, rule.Type) {
+			sc.report(path, SeverityError, "allowed value %v does not match type '%s'", allowed, rule.Type)
 		}
+	}
 
-		// 4. Validar Min y Max solo en números
-		if (rule.Min != 0 || rule.Max != 0) && rule.Type != "int" && rule.Type != "float" {
-			return fmt.Errorf("min/max can only be used for numeric fields, but found in '%s'", field)
+	// 5. Validar Min y Max solo en números
+	if rule.Min != 0 || rule.Max != 0 {
+		if rule.Type != "int" && rule.Type != "float" {
+			sc.report(path, SeverityError, "min/max can only be used for numeric fields")
+		} else if rule.Min != 0 && rule.Max != 0 && rule.Min > rule.Max {
+			sc.report(path, SeverityError, "min %v is greater than max %v", rule.Min, rule.Max)
 		}
+	}
 
-		// 5. Validar listas y mapas anidados
-		if rule.Type == "list" && rule.List != nil {
-			if err := ValidateSchema(Schema{"items": *rule.List}); err != nil {
-				return fmt.Errorf("invalid list schema in '%s': %v", field, err)
-			}
+	// 6. Validar longitudes y regex solo en strings
+	if rule.MinLength != 0 || rule.MaxLength != 0 {
+		switch {
+		case rule.Type != "string":
+			sc.report(path, SeverityError, "min_length/max_length can only be used for string fields")
+		case rule.MinLength < 0 || rule.MaxLength < 0:
+			sc.report(path, SeverityError, "min_length and max_length cannot be negative")
+		case rule.MaxLength != 0 && rule.MinLength > rule.MaxLength:
+			sc.report(path, SeverityError, "min_length %d is greater than max_length %d", rule.MinLength, rule.MaxLength)
+		}
+	}
+	if rule.Regex != nil || rule.RegexPattern != "" {
+		if rule.Type != "string" {
+			sc.report(path, SeverityError, "regex can only be used for string fields")
+		} else if rule.Regex == nil {
+			sc.report(path, SeverityWarning, "regex '%s' is not compiled and is ignored; load the schema with LoadSchema or set Regex", rule.RegexPattern)
+		}
+	}
+
+	// 7. Validar formatos y checks registrados
+	if rule.Format != "" {
+		if rule.Type != "string" {
+			sc.report(path, SeverityError, "format can only be used for string fields")
+		} else if sc.format(rule.Format) == nil {
+			sc.report(path, SeverityError, "unknown format '%s'", rule.Format)
+		}
+	}
+	if rule.Check != "" && sc.checks[rule.Check] == nil && sc.external[rule.Check] == nil {
+		sc.report(path, SeverityError, "unknown check '%s'", rule.Check)
+	}
+
+	// 8. Validar listas y mapas anidados
+	if rule.List != nil {
+		if rule.Type != "list" {
+			sc.report(path, SeverityError, "list rule can only be used for list fields")
+		} else {
+			sc.rule(*rule.List, path+".items")
+		}
+	}
+	if rule.Schema != nil {
+		if rule.Type != "map" {
+			sc.report(path, SeverityError, "nested schema can only be used for map fields")
+		} else {
+			sc.schema(*rule.Schema, path+".schema.")
+		}
+	}
+
+	sc.severity(rule, path)
+	sc.messages(rule, path)
+	sc.examples(rule, path)
+}
+
+// constraintCodes are the codes that Rule.Warn can name.
+var constraintCodes = map[string]bool{
+	CodeRequired: true, CodeTypeMismatch: true, CodeMin: true, CodeMax: true,
+	CodeMinLength: true, CodeMaxLength: true, CodePattern: true,
+	CodeAllowed: true, CodeFormat: true, CodeCheck: true,
+}
+
+// severity checks Severity and Warn.
+func (sc *schemaChecker) severity(rule Rule, path string) {
+	if rule.Severity != SeverityError && rule.Severity != SeverityWarning {
+		sc.report(path, SeverityError, "invalid severity %d", int(rule.Severity))
+	}
+	for _, code := range rule.Warn {
+		if !constraintCodes[code] {
+			sc.report(path, SeverityError, "warn names unknown constraint '%s'", code)
 		}
+	}
+}
 
-		if rule.Type == "map" && rule.Schema != nil {
-			if err := ValidateSchema(*rule.Schema); err != nil {
-				return fmt.Errorf("invalid map schema in '%s': %v", field, err)
+// examples reports the Examples of rule that it would reject. It only runs
+// once the rule itself has no errors.
+func (sc *schemaChecker) examples(rule Rule, path string) {
+	if len(rule.Examples) == 0 {
+		return
+	}
+	for _, problem := range sc.problems {
+		if problem.Severity == SeverityError && (problem.Path == path || strings.HasPrefix(problem.Path, path+".")) {
+			return
+		}
+	}
+	for i, example := range rule.Examples {
+		v := sc.newValidation(context.Background())
+		for _, err := range v.validateField("example", example, rule, nil) {
+			if err.Severity == SeverityWarning {
+				continue
+			}
+			msg := err.Message
+			if len(err.Path) > 1 {
+				msg = err.Path[1:].String() + ": " + msg
 			}
+			sc.report(path, SeverityError, "example %d is invalid: %s", i, msg)
 		}
 	}
+}
 
-	return nil
+// messages warns about custom messages that rule can never produce.
+func (sc *schemaChecker) messages(rule Rule, path string) {
+	m := rule.Messages
+	if m == nil {
+		return
+	}
+	numeric := rule.Type == "int" || rule.Type == "float"
+	hasRegex := rule.Regex != nil || rule.RegexPattern != ""
+	unused := []struct {
+		set    bool
+		name   string
+		reason string
+	}{
+		{m.Required != nil && !rule.Required, "required", "the field is not required"},
+		{m.Range != nil && (!numeric || (rule.Min == 0 && rule.Max == 0)), "range", "there is no min or max"},
+		{m.Length != nil && (rule.Type != "string" || (rule.MinLength == 0 && rule.MaxLength == 0 && !hasRegex)), "length", "there is no min_length, max_length or regex"},
+		{m.Pattern != nil && (rule.Type != "string" || !hasRegex), "pattern", "there is no regex"},
+		{m.Allowed != nil && rule.Allowed == nil, "allowed", "there are no allowed values"},
+		{m.Format != nil && (rule.Type != "string" || rule.Format == ""), "format", "there is no format"},
+		{m.Check != nil && rule.Check == "", "check", "there is no check"},
+	}
+	for _, u := range unused {
+		if u.set {
+			sc.report(path, SeverityWarning, "messages.%s never applies because %s", u.name, u.reason)
+		}
+	}
 }
 
 // Validate checks if the provided data conforms to the specified schema and returns a ValidationResult.
@@ -147,89 +358,447 @@ func ValidateSchema(schema Schema) error {
 //	A ValidationResult containing:
 //	- IsValid: A boolean indicating whether all validations passed
 //	- Errors: A slice of ValidationError objects describing each validation failure
+//
+// Validate uses a Validator with default options; use New to configure one.
+// Rules with a Ref are reported as unresolved; use Registry.Validate for
+// schemas that use refs.
 func Validate(data map[string]interface{}, schema Schema) ValidationResult {
+	return defaultValidator.Validate(data, schema)
+}
+
+// Validator validates data against schemas with a fixed configuration. It
+// is safe for concurrent use by multiple goroutines.
+type Validator struct {
+	failFast      bool
+	maxErrors     int
+	rejectUnknown bool
+	partial       bool
+	mergePatch    bool
+	locale        Locale
+	types         map[string]TypeFunc
+	formats       map[string]FormatFunc
+	checks        map[string]CheckFunc
+	now           func() time.Time
+	registry      *Registry
+	maxDepth      int
+
+	external            map[string]ExternalCheck
+	externalConcurrency int
+
+	parallelThreshold int
+	parallelWorkers   int
+
+	promoteWarnings bool
+}
+
+// defaultValidator backs the package-level functions.
+var defaultValidator = New()
+
+// New returns a Validator configured by opts.
+func New(opts ...Option) *Validator {
+	v := &Validator{
+		types:    map[string]TypeFunc{},
+		formats:  map[string]FormatFunc{},
+		checks:   map[string]CheckFunc{},
+		now:      time.Now,
+		external: map[string]ExternalCheck{},
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Validate checks data against schema as described for the package-level
+// Validate, with the options of c applied.
+func (c *Validator) Validate(data map[string]interface{}, schema Schema) ValidationResult {
+	result, _ := c.ValidateContext(context.Background(), data, schema)
+	return result
+}
+
+// ValidateContext is like the package-level ValidateContext, with the
+// options of c applied.
+func (c *Validator) ValidateContext(ctx context.Context, data map[string]interface{}, schema Schema) (ValidationResult, error) {
+	v := c.newValidation(ctx)
+	var result ValidationResult
+	for _, err := range append(v.validate(data, schema).Errors, v.runExternal()...) {
+		err.Field = err.Path.String()
+		if err.Severity == SeverityWarning {
+			result.Warnings = append(result.Warnings, err)
+		} else {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+	result.IsValid = len(result.Errors) == 0
+	result.Truncated = v.stopped
+	// Drop the error that stopped the run, and any found along with it
+	if v.limit > 0 && len(result.Errors) > v.limit {
+		result.Errors = result.Errors[:v.limit]
+		result.Truncated = true
+	}
+	if v.err != nil {
+		result.IsValid = false
+	}
+	return result, v.err
+}
+
+// ValidateContext is like Validate but stops as soon as ctx is done, which
+// keeps a huge payload from occupying a request handler after its deadline.
+// It then returns ctx.Err() along with the errors found so far, in a result
+// that is marked Truncated and not valid. The context is also available to
+// custom checks through CheckRequest.Context.
+func ValidateContext(ctx context.Context, data map[string]interface{}, schema Schema) (ValidationResult, error) {
+	return defaultValidator.ValidateContext(ctx, data, schema)
+}
+
+// matchesType is like the package-level matchesType but also knows the
+// custom types of c.
+func (c *Validator) matchesType(value interface{}, expectedType string) bool {
+	if builtinTypes[expectedType] {
+		return matchesType(value, expectedType)
+	}
+	if fn := c.types[expectedType]; fn != nil {
+		return fn(value)
+	}
+	return false
+}
+
+// format returns the format registered under name, falling back to the
+// built-in formats.
+func (c *Validator) format(name string) FormatFunc {
+	if fn := c.formats[name]; fn != nil {
+		return fn
+	}
+	return builtinFormats[name]
+}
+
+// DefaultMaxDepth is the maximum nesting depth of lists and maps that
+// validation descends into unless WithMaxDepth or Registry.MaxDepth says
+// otherwise. Without a limit, a recursive schema would let a maliciously
+// deep payload exhaust the stack.
+const DefaultMaxDepth = 64
+
+// validation holds the state shared by one validation run and its recursion.
+type validation struct {
+	*Validator
+	now   time.Time // Time of the run, for CheckRequest.Now
+	depth int       // Number of lists and maps entered so far
+	// limit is the number of errors to report, or zero. count is the number
+	// of errors so far and stopped is set once it exceeds limit: the run
+	// goes on until an error is actually left out, so that Truncated is
+	// only set when the data has more errors than reported.
+	limit   int
+	count   int
+	stopped bool
+	// ctx is checked between fields and list items; err records why it
+	// stopped the run.
+	ctx context.Context
+	err error
+	// pending collects external checks with paths relative to the map
+	// being validated; they are prefixed on the way back up.
+	pending []pendingCheck
+	// partial and mergePatch start out from the Validator and are turned
+	// off inside lists, whose items are always complete values.
+	partial    bool
+	mergePatch bool
+}
+
+// newValidation prepares the state for one validation run.
+func (c *Validator) newValidation(ctx context.Context) *validation {
+	limit := c.maxErrors
+	if c.failFast {
+		limit = 1
+	}
+	return &validation{
+		Validator:  c,
+		limit:      limit,
+		ctx:        ctx,
+		now:        c.now(),
+		partial:    c.partial || c.mergePatch,
+		mergePatch: c.mergePatch,
+	}
+}
+
+// depthLimit returns the nesting limit for this run.
+func (v *validation) depthLimit() int {
+	if v.maxDepth > 0 {
+		return v.maxDepth
+	}
+	if v.registry != nil && v.registry.MaxDepth > 0 {
+		return v.registry.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+// fail turns a failed constraint into a ValidationError, using the custom
+// message if it is set and the locale otherwise.
+func (v *validation) fail(field string, f violation, custom *string) ValidationError {
+	v.count++
+	if v.limit > 0 && v.count > v.limit {
+		v.stopped = true
+	}
+	return ValidationError{Field: field, Path: Path{KeySegment(field)}, Code: f.code, Message: v.locale.render(f, custom)}
+}
+
+// warn is like fail for findings that do not make the data invalid. They do
+// not count towards the error limit, unless WithPromoteWarnings makes them
+// errors.
+func (v *validation) warn(field string, f violation, custom *string) ValidationError {
+	if v.promoteWarnings {
+		return v.fail(field, f, custom)
+	}
+	return ValidationError{Field: field, Path: Path{KeySegment(field)}, Code: f.code, Message: v.locale.render(f, custom), Severity: SeverityWarning}
+}
+
+// report fails or warns about a violation of rule, depending on the
+// severity the rule gives it.
+func (v *validation) report(rule Rule, field string, f violation, custom *string) ValidationError {
+	if rule.severity(f.code) == SeverityWarning {
+		return v.warn(field, f, custom)
+	}
+	return v.fail(field, f, custom)
+}
+
+// severity returns the severity of a violation of the constraint code.
+func (r Rule) severity(code string) Severity {
+	if r.Severity == SeverityWarning {
+		return SeverityWarning
+	}
+	for _, c := range r.Warn {
+		if c == code {
+			return SeverityWarning
+		}
+	}
+	return SeverityError
+}
+
+// hasErrors reports whether errs holds anything but warnings.
+func hasErrors(errs []ValidationError) bool {
+	for _, err := range errs {
+		if err.Severity != SeverityWarning {
+			return true
+		}
+	}
+	return false
+}
+
+// depthError reports a value nested deeper than depthLimit.
+func (v *validation) depthError(field string) ValidationError {
+	limit := v.depthLimit()
+	return v.fail(field, violation{CodeMaxDepth, fmt.Sprintf("Maximum nesting depth %d exceeded", limit),
+		map[string]interface{}{"max": limit}}, nil)
+}
+
+// done reports whether the run should stop, either because of the error
+// limit or because the context is done.
+func (v *validation) done() bool {
+	if v.stopped {
+		return true
+	}
+	select {
+	case <-v.ctx.Done():
+		v.err = v.ctx.Err()
+		v.stopped = true
+		return true
+	default:
+		return false
+	}
+}
+
+// sortedFields returns the keys of data in order, so that the order of the
+// errors, and which ones are reported before an error limit, does not
+// depend on map iteration.
+func sortedFields(data map[string]interface{}) []string {
+	fields := make([]string, 0, len(data))
+	for field := range data {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// messages returns the custom messages of rule, never nil.
+func messages(rule Rule) *Messages {
+	if rule.Messages != nil {
+		return rule.Messages
+	}
+	return &Messages{}
+}
+
+func (v *validation) validate(data map[string]interface{}, schema Schema) ValidationResult {
 	var validationErrors []ValidationError
 
 	// Validate provided data against schema
-	for field, value := range data {
+	for _, field := range sortedFields(data) {
+		if v.done() {
+			break
+		}
+		value := data[field]
 		rule, exists := schema[field]
 		if !exists {
+			if v.rejectUnknown {
+				validationErrors = append(validationErrors, v.fail(field, violation{code: CodeUnknownField, message: "Field is not allowed"}, nil))
+			}
 			continue // Skip fields not in schema
 		}
 
-		// Type validation
-		if !matchesType(value, rule.Type) {
-			msg := fmt.Sprintf("Invalid type: expected %s, got %T", rule.Type, value)
-			if rule.Messages != nil && rule.Messages.TypeMismatch != nil {
-				msg = *rule.Messages.TypeMismatch
+		validationErrors = append(validationErrors, v.validateField(field, value, rule, data)...)
+	}
+
+	// Check for required fields, which a partial update may leave out
+	if !v.partial && !v.stopped {
+		fields := make([]string, 0, len(schema))
+		for field := range schema {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			if v.done() {
+				break
+			}
+			rule := schema[field]
+			if resolved, err := v.registry.resolve(rule); err == nil {
+				rule = resolved
 			}
-			validationErrors = append(validationErrors, ValidationError{Field: field, Message: msg})
-			continue
+			if rule.Required {
+				if _, exists := data[field]; !exists {
+					validationErrors = append(validationErrors, v.report(rule, field, violation{code: CodeRequired,
+						message: "Field is required"}, messages(rule).Required))
+				}
+			}
+		}
+	}
+
+	return ValidationResult{
+		IsValid: !hasErrors(validationErrors),
+		Errors:  validationErrors,
+	}
+}
+
+// validateField checks one value against its rule. data is the map holding
+// the value, or nil for a list item.
+func (v *validation) validateField(field string, value interface{}, rule Rule, data map[string]interface{}) []ValidationError {
+	var validationErrors []ValidationError
+
+	// Reference resolution
+	rule, err := v.registry.resolve(rule)
+	if err != nil {
+		validationErrors = append(validationErrors, v.fail(field, violation{CodeReference, err.Error(),
+			map[string]interface{}{"error": err}}, nil))
+		return validationErrors
+	}
+	msgs := messages(rule)
+
+	// In a merge patch, null removes the field
+	if value == nil && v.mergePatch {
+		if rule.Required {
+			validationErrors = append(validationErrors, v.report(rule, field, violation{code: CodeRequired,
+				message: "Field is required and cannot be removed"}, msgs.Required))
 		}
+		return validationErrors
+	}
+
+	// Deprecated fields are accepted with a warning
+	if rule.Deprecated != "" {
+		validationErrors = append(validationErrors, v.warn(field, violation{CodeDeprecated,
+			fmt.Sprintf("Field is deprecated: %s", rule.Deprecated), map[string]interface{}{"reason": rule.Deprecated}}, nil))
+	}
+
+	// Type validation
+	if !v.matchesType(value, rule.Type) {
+		validationErrors = append(validationErrors, v.report(rule, field, violation{CodeTypeMismatch,
+			fmt.Sprintf("Invalid type: expected %s, got %T", rule.Type, value),
+			map[string]interface{}{"type": rule.Type, "value": value}}, msgs.TypeMismatch))
+		return validationErrors
+	}
 
-		// Type-specific validations
-		switch rule.Type {
-		case "int", "float":
-			if valid, errMsg := validateNumeric(value, rule); !valid {
-				if rule.Messages != nil && rule.Messages.Range != nil {
-					errMsg = *rule.Messages.Range
+	// Type-specific validations
+	switch rule.Type {
+	case "int", "float":
+		if valid, f := validateNumeric(value, rule); !valid {
+			validationErrors = append(validationErrors, v.report(rule, field, f, msgs.Range))
+		}
+	case "string":
+		if strVal, ok := value.(string); ok {
+			if valid, f := validateString(strVal, rule); !valid {
+				custom := msgs.Length
+				if f.code == CodePattern && msgs.Pattern != nil {
+					custom = msgs.Pattern
 				}
-				validationErrors = append(validationErrors, ValidationError{Field: field, Message: errMsg})
+				validationErrors = append(validationErrors, v.report(rule, field, f, custom))
 			}
-		case "string":
-			if strVal, ok := value.(string); ok {
-				if valid, errMsg := validateString(strVal, rule); !valid {
-					if rule.Messages != nil && rule.Messages.Length != nil {
-						errMsg = *rule.Messages.Length
-					}
-					validationErrors = append(validationErrors, ValidationError{Field: field, Message: errMsg})
+			if rule.Format != "" {
+				if fn := v.format(rule.Format); fn == nil {
+					validationErrors = append(validationErrors, v.fail(field, violation{CodeFormat,
+						fmt.Sprintf("Unknown format '%s'", rule.Format), nil}, nil))
+				} else if !fn(strVal) {
+					validationErrors = append(validationErrors, v.report(rule, field, violation{CodeFormat,
+						fmt.Sprintf("Value is not a valid %s", rule.Format),
+						map[string]interface{}{"format": rule.Format, "value": strVal}}, msgs.Format))
 				}
 			}
-		case "list":
-			if listVal, ok := value.([]interface{}); ok && rule.List != nil {
-				for i, item := range listVal {
-					itemData := map[string]interface{}{"items": item}
-					result := Validate(itemData, Schema{"items": *rule.List})
-					if !result.IsValid {
-						for _, err := range result.Errors {
-							validationErrors = append(validationErrors, ValidationError{
-								Field:   fmt.Sprintf("%s[%d]", field, i),
-								Message: err.Message,
-							})
-						}
-					}
-				}
+		}
+	case "list":
+		if listVal, ok := value.([]interface{}); ok && rule.List != nil {
+			if v.depth >= v.depthLimit() {
+				validationErrors = append(validationErrors, v.depthError(field))
+				break
 			}
-		case "map":
-			if mapVal, ok := value.(map[string]interface{}); ok && rule.Schema != nil {
-				result := Validate(mapVal, *rule.Schema)
-				if !result.IsValid {
-					for _, err := range result.Errors {
-						validationErrors = append(validationErrors, ValidationError{
-							Field:   fmt.Sprintf("%s.%s", field, err.Field),
-							Message: err.Message,
-						})
-					}
-				}
+			v.depth++
+			partial, mergePatch := v.partial, v.mergePatch
+			v.partial, v.mergePatch = false, false
+			validationErrors = append(validationErrors, v.validateList(field, listVal, *rule.List)...)
+			v.partial, v.mergePatch = partial, mergePatch
+			v.depth--
+		}
+	case "map":
+		if mapVal, ok := value.(map[string]interface{}); ok && rule.Schema != nil {
+			if v.depth >= v.depthLimit() {
+				validationErrors = append(validationErrors, v.depthError(field))
+				break
+			}
+			v.depth++
+			mark := len(v.pending)
+			result := v.validate(mapVal, *rule.Schema)
+			v.prefixPending(mark, func(path Path) Path { return append(Path{KeySegment(field)}, path...) })
+			v.depth--
+			for _, err := range result.Errors {
+				err.Path = append(Path{KeySegment(field)}, err.Path...)
+				validationErrors = append(validationErrors, err)
 			}
 		}
 	}
 
-	// Check for required fields
-	for field, rule := range schema {
-		if rule.Required {
-			if _, exists := data[field]; !exists {
-				msg := "Field is required"
-				if rule.Messages != nil && rule.Messages.Required != nil {
-					msg = *rule.Messages.Required
-				}
-				validationErrors = append(validationErrors, ValidationError{Field: field, Message: msg})
-			}
+	// Allowed values
+	if rule.Allowed != nil && !isAllowed(value, rule.Allowed) {
+		validationErrors = append(validationErrors, v.report(rule, field, violation{CodeAllowed,
+			fmt.Sprintf("Value %v is not one of the allowed values %v", value, rule.Allowed),
+			map[string]interface{}{"allowed": rule.Allowed, "value": value}}, msgs.Allowed))
+	}
+
+	// Custom checks run once everything else about the field passed
+	if rule.Check != "" && !hasErrors(validationErrors) {
+		if _, ok := v.external[rule.Check]; ok {
+			v.pending = append(v.pending, pendingCheck{path: Path{KeySegment(field)}, value: value, check: rule.Check,
+				custom: messages(rule).Check, severity: rule.severity(CodeCheck)})
+		} else {
+			validationErrors = append(validationErrors, v.check(field, value, data, rule)...)
 		}
 	}
 
-	return ValidationResult{
-		IsValid: len(validationErrors) == 0,
-		Errors:  validationErrors,
+	return validationErrors
+}
+
+// check runs the custom check named by rule.Check.
+func (v *validation) check(field string, value interface{}, data map[string]interface{}, rule Rule) []ValidationError {
+	fn := v.checks[rule.Check]
+	if fn == nil {
+		return []ValidationError{v.fail(field, violation{CodeCheck, fmt.Sprintf("Unknown check '%s'", rule.Check), nil}, nil)}
+	}
+	err := fn(CheckRequest{Field: field, Value: value, Data: data, Now: v.now, ctx: v.ctx})
+	if err == nil {
+		return nil
 	}
+	return []ValidationError{v.report(rule, field, violation{CodeCheck, err.Error(),
+		map[string]interface{}{"check": rule.Check, "error": err}}, messages(rule).Check)}
 }
@@ -1,6 +1,9 @@
 package validator
 
 import (
+	"errors"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -565,3 +568,243 @@ func TestSchemaValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestNullValue tests that JSON null values are reported instead of panicking
+func TestNullValue(t *testing.T) {
+	schema := Schema{
+		"name": {Type: "string"},
+	}
+
+	result := Validate(map[string]interface{}{"name": nil}, schema)
+	if result.IsValid {
+		t.Errorf("Expected validation to fail for null value")
+	}
+}
+
+// TestAllowedValues tests the allowed values rule
+func TestAllowedValues(t *testing.T) {
+	schema := Schema{
+		"color": {Type: "string", Allowed: []interface{}{"red", "green"}},
+		"size": {
+			Type:     "int",
+			Allowed:  []interface{}{1, 2, 3},
+			Messages: &Messages{Allowed: strPtr("Size must be 1, 2 or 3")},
+		},
+	}
+
+	result := Validate(map[string]interface{}{"color": "red", "size": float64(2)}, schema)
+	if !result.IsValid {
+		t.Errorf("Expected valid data, got errors: %v", result.Errors)
+	}
+
+	result = Validate(map[string]interface{}{"color": "blue", "size": 4}, schema)
+	if len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", result.Errors)
+	}
+	for _, err := range result.Errors {
+		if err.Field == "size" && err.Message != "Size must be 1, 2 or 3" {
+			t.Errorf("Expected custom allowed message, got %q", err.Message)
+		}
+	}
+
+	if err := ValidateSchema(Schema{"color": {Type: "string", Allowed: []interface{}{"red", 1}}}); err == nil {
+		t.Errorf("Expected error for allowed value of the wrong type")
+	}
+}
+
+func TestSchemaProblems(t *testing.T) {
+	schema := Schema{
+		"bad key": {Type: "string"},
+		"age":     {Type: "int", Min: 30, Max: 10, Messages: &Messages{Length: strPtr("never")}},
+		"name":    {Type: "string", MinLength: 5, MaxLength: 2, RegexPattern: "^a"},
+		"tags":    {Type: "string", List: &Rule{Type: "string"}},
+		"meta":    {Type: "list", Schema: &Schema{}, MaxLength: 3},
+		"orders": {Type: "list", List: &Rule{Type: "map", Schema: &Schema{
+			"price": {Type: "float", Regex: regexp.MustCompile("x"), Messages: &Messages{Required: strPtr("never")}},
+			"qty":   {Type: "number"},
+		}}},
+	}
+
+	var got []string
+	for _, p := range SchemaProblems(schema) {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"age: min 30 is greater than max 10",
+		"age: warning: messages.length never applies because there is no min_length, max_length or regex",
+		"bad key: invalid field name: 'bad key'",
+		"meta: min_length/max_length can only be used for string fields",
+		"meta: nested schema can only be used for map fields",
+		"name: min_length 5 is greater than max_length 2",
+		"name: warning: regex '^a' is not compiled and is ignored; load the schema with LoadSchema or set Regex",
+		"orders.items.schema.price: regex can only be used for string fields",
+		"orders.items.schema.price: warning: messages.required never applies because the field is not required",
+		"orders.items.schema.qty: invalid type 'number'",
+		"tags: list rule can only be used for list fields",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	err := ValidateSchema(schema)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || len(schemaErr.Problems) != len(want) {
+		t.Fatalf("Expected a SchemaError with every problem, got %v", err)
+	}
+	if strings.Contains(err.Error(), "warning") || !strings.Contains(err.Error(), "orders.items.schema.qty: invalid type 'number'") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+
+	warningsOnly := Schema{"nick": {Type: "string", Messages: &Messages{Pattern: strPtr("never")}}}
+	if err := ValidateSchema(warningsOnly); err != nil {
+		t.Errorf("Expected warnings not to invalidate the schema, got %v", err)
+	}
+	if problems := SchemaProblems(warningsOnly); len(problems) != 1 || problems[0].Severity != SeverityWarning {
+		t.Errorf("Expected one warning, got %v", problems)
+	}
+}
+
+func TestRuleMetadata(t *testing.T) {
+	schema := Schema{
+		"name": {Type: "string", Required: true, Title: "Name", Description: "Full name", Examples: []interface{}{"Ada"}},
+		"fax":  {Type: "string", MaxLength: 5, Deprecated: "use phone instead"},
+		"address": {Type: "map", Schema: &Schema{
+			"zip": {Type: "string", Deprecated: "use postal_code"},
+		}},
+	}
+	if err := ValidateSchema(schema); err != nil {
+		t.Fatalf("unexpected schema error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		valid    bool
+		warnings []string
+	}{
+		{"no deprecated fields", map[string]interface{}{"name": "Ada"}, true, nil},
+		{"deprecated field", map[string]interface{}{"name": "Ada", "fax": "123"}, true, []string{"fax"}},
+		{"nested deprecated field", map[string]interface{}{"name": "Ada", "address": map[string]interface{}{"zip": "1"}}, true, []string{"address.zip"}},
+		{"deprecated field with an error", map[string]interface{}{"name": "Ada", "fax": "1234567"}, false, []string{"fax"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, schema)
+			if result.IsValid != tt.valid {
+				t.Errorf("Expected IsValid %v, got %v: %v", tt.valid, result.IsValid, result.Errors)
+			}
+			var fields []string
+			for _, w := range result.Warnings {
+				if w.Code != CodeDeprecated || w.Severity != SeverityWarning {
+					t.Errorf("unexpected warning %+v", w)
+				}
+				fields = append(fields, w.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.warnings, ",") {
+				t.Errorf("Expected warnings for %v, got %v", tt.warnings, result.Warnings)
+			}
+			for _, err := range result.Errors {
+				if err.Severity == SeverityWarning {
+					t.Errorf("Expected no warnings in Errors, got %+v", err)
+				}
+			}
+		})
+	}
+
+	t.Run("warnings do not count towards the error limit", func(t *testing.T) {
+		result := New(WithFailFast(true)).Validate(map[string]interface{}{"fax": "1234567", "address": map[string]interface{}{"zip": "1"}}, schema)
+		if len(result.Errors) != 1 || result.Errors[0].Field != "fax" {
+			t.Errorf("Expected the fax error first, got %v", result.Errors)
+		}
+	})
+
+	t.Run("invalid examples", func(t *testing.T) {
+		bad := Schema{
+			"age":  {Type: "int", Min: 18, Examples: []interface{}{21, 12, "x"}},
+			"user": {Type: "map", Schema: &Schema{"id": {Type: "int", Required: true}}, Examples: []interface{}{map[string]interface{}{}}},
+			"skip": {Type: "int", Min: 5, Max: 1, Examples: []interface{}{"x"}},
+		}
+		var got []string
+		for _, p := range SchemaProblems(bad) {
+			got = append(got, p.String())
+		}
+		want := []string{
+			"age: example 1 is invalid: Value 12 is less than minimum 18",
+			"age: example 2 is invalid: Invalid type: expected int, got string",
+			"skip: min 5 is greater than max 1",
+			"user: example 0 is invalid: id: Field is required",
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("got problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	})
+}
+
+func TestWarningSeverity(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{
+		"phone": {"type": "string", "required": true, "severity": "warning"},
+		"bio":   {"type": "string", "max_length": 10, "warn": ["max_length"]},
+		"age":   {"type": "int", "min": 18, "max": 99, "warn": ["max"]},
+		"fax":   {"type": "string", "deprecated": "use phone"}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseSchema returned error: %v", err)
+	}
+	if err := ValidateSchema(schema); err != nil {
+		t.Fatalf("unexpected schema error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		errors   []string
+		warnings []string
+	}{
+		{"soft limits exceeded", map[string]interface{}{"bio": "far too long a bio", "age": 120}, nil,
+			[]string{"age:" + CodeMax, "bio:" + CodeMaxLength, "phone:" + CodeRequired}},
+		{"hard limit stays an error", map[string]interface{}{"phone": "1", "age": 12}, []string{"age:" + CodeMin}, nil},
+		{"warning and deprecation", map[string]interface{}{"phone": "1", "fax": "2", "bio": "x"}, nil,
+			[]string{"fax:" + CodeDeprecated}},
+	}
+	label := func(errs []ValidationError) []string {
+		var labels []string
+		for _, e := range errs {
+			labels = append(labels, e.Field+":"+e.Code)
+		}
+		return labels
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, schema)
+			if result.IsValid != (len(tt.errors) == 0) {
+				t.Errorf("Expected IsValid %v, got %v", len(tt.errors) == 0, result.IsValid)
+			}
+			if got := label(result.Errors); strings.Join(got, ",") != strings.Join(tt.errors, ",") {
+				t.Errorf("Expected errors %v, got %v", tt.errors, got)
+			}
+			if got := label(result.Warnings); strings.Join(got, ",") != strings.Join(tt.warnings, ",") {
+				t.Errorf("Expected warnings %v, got %v", tt.warnings, got)
+			}
+
+			promoted := New(WithPromoteWarnings(true)).Validate(tt.data, schema)
+			want := len(tt.errors) + len(tt.warnings)
+			if promoted.IsValid != (want == 0) || len(promoted.Errors) != want || len(promoted.Warnings) != 0 {
+				t.Errorf("Expected %d promoted errors, got %v and warnings %v", want, promoted.Errors, promoted.Warnings)
+			}
+		})
+	}
+
+	t.Run("invalid severities", func(t *testing.T) {
+		var got []string
+		for _, p := range SchemaProblems(Schema{
+			"a": {Type: "int", Severity: Severity(7)},
+			"b": {Type: "int", Warn: []string{CodeMax, "too_big"}},
+		}) {
+			got = append(got, p.String())
+		}
+		want := "a: invalid severity 7\nb: warn names unknown constraint 'too_big'"
+		if strings.Join(got, "\n") != want {
+			t.Errorf("got problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), want)
+		}
+	})
+}